@@ -0,0 +1,110 @@
+package awsqueries
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// ArchiveFile describe a file stored in a CodePipeline artifact archive
+type ArchiveFile struct {
+	Name     string
+	Size     uint64
+	Modified time.Time
+}
+
+// ListS3ArchiveFiles returns the files stored in a zip archive hosted on S3
+func ListS3ArchiveFiles(cfg aws.Config, bucket, key string) ([]ArchiveFile, error) {
+	archive, err := openS3Archive(cfg, bucket, key, "")
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	files := make([]ArchiveFile, 0, len(archive.File))
+	for _, f := range archive.File {
+		files = append(files, ArchiveFile{
+			Name:     f.Name,
+			Size:     f.UncompressedSize64,
+			Modified: f.Modified,
+		})
+	}
+	return files, nil
+}
+
+// DownloadS3Object saves an S3 object to a local file
+func DownloadS3Object(cfg aws.Config, bucket, key, dst string) error {
+	body, err := getS3ObjectBody(cfg, bucket, key, "")
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, body)
+	return err
+}
+
+// ExtractS3Archive extracts a zip archive hosted on S3 in a local directory
+func ExtractS3Archive(cfg aws.Config, bucket, key, dir string) error {
+	archive, err := openS3Archive(cfg, bucket, key, "")
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range archive.File {
+		if err := extractZipFile(f, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractZipFile(f *zip.File, dir string) error {
+	dst := filepath.Join(dir, f.Name)
+	// Protect against archive entries escaping the destination directory, ./ is the directory itself
+	if dst != dir && !strings.HasPrefix(dst, dir+string(os.PathSeparator)) {
+		return fmt.Errorf("invalid file path in archive: %s", f.Name)
+	}
+
+	if f.FileInfo().IsDir() {
+		return os.MkdirAll(dst, os.ModePerm)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, f.Mode().Perm()|0600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, rc)
+	return err
+}
@@ -0,0 +1,83 @@
+package awsqueries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+)
+
+// CloudFormationData holds the details of a CloudFormation stack deployed by a CodePipeline action
+type CloudFormationData struct {
+	Stack     *types.Stack
+	Events    []types.StackEvent
+	ChangeSet *cloudformation.DescribeChangeSetOutput
+}
+
+// GetCloudFormationData returns the stack, its latest events and the change set if a name is provided
+func GetCloudFormationData(cfg aws.Config, stackName, changeSetName string, maxEvents int) (CloudFormationData, error) {
+	var data CloudFormationData
+	client := cloudformation.NewFromConfig(cfg)
+
+	stacks, err := client.DescribeStacks(context.Background(), &cloudformation.DescribeStacksInput{
+		StackName: aws.String(stackName),
+	})
+	if err != nil {
+		return data, err
+	}
+	if len(stacks.Stacks) != 1 {
+		return data, fmt.Errorf("multiple stacks returned for %v", stackName)
+	}
+	data.Stack = &stacks.Stacks[0]
+
+	paginator := cloudformation.NewDescribeStackEventsPaginator(client, &cloudformation.DescribeStackEventsInput{
+		StackName: aws.String(stackName),
+	})
+	for paginator.HasMorePages() && len(data.Events) < maxEvents {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return data, err
+		}
+		data.Events = append(data.Events, page.StackEvents...)
+	}
+	if len(data.Events) > maxEvents {
+		data.Events = data.Events[:maxEvents]
+	}
+
+	if changeSetName != "" {
+		data.ChangeSet, err = GetCloudFormationChangeSet(cfg, stackName, changeSetName)
+		if err != nil {
+			return data, err
+		}
+	}
+	return data, nil
+}
+
+// GetCloudFormationChangeSet returns a change set with all its resource changes
+func GetCloudFormationChangeSet(cfg aws.Config, stackName, changeSetName string) (*cloudformation.DescribeChangeSetOutput, error) {
+	client := cloudformation.NewFromConfig(cfg)
+
+	input := &cloudformation.DescribeChangeSetInput{
+		StackName:     aws.String(stackName),
+		ChangeSetName: aws.String(changeSetName),
+	}
+
+	changeSet, err := client.DescribeChangeSet(context.Background(), input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resource changes are paginated, merge them in the first page
+	for token := changeSet.NextToken; token != nil; {
+		input.NextToken = token
+		page, err := client.DescribeChangeSet(context.Background(), input)
+		if err != nil {
+			return nil, err
+		}
+		changeSet.Changes = append(changeSet.Changes, page.Changes...)
+		token = page.NextToken
+	}
+	return changeSet, nil
+}
@@ -16,15 +16,15 @@ type CodebuildData struct {
 }
 
 // GetCodeBuildData returns a list of codebuild details
-func GetCodeBuildData(cfg aws.Config, accountID, name, buildID string) (CodebuildData, error) {
+func GetCodeBuildData(cfg aws.Config, name, buildID string) (CodebuildData, error) {
 	var cb CodebuildData
 	var err error
 	cb.Name = name
-	cb.Builds, err = GetCodeBuildBuilds(cfg, accountID, buildID)
+	cb.Builds, err = GetCodeBuildBuilds(cfg, buildID)
 	if err != nil {
 		return cb, err
 	}
-	cb.Project, err = GetCodeBuildProjects(cfg, accountID, name)
+	cb.Project, err = GetCodeBuildProjects(cfg, name)
 	if err != nil {
 		return cb, err
 	}
@@ -34,34 +34,24 @@ func GetCodeBuildData(cfg aws.Config, accountID, name, buildID string) (Codebuil
 	return cb, nil
 }
 
-// GetCodeBuildBuilds returns a list of buils details
-func GetCodeBuildBuilds(cfg aws.Config, accountID, buildID string) (*codebuild.BatchGetBuildsOutput, error) {
-	var builds *codebuild.BatchGetBuildsOutput
-	var err error
-
+// GetCodeBuildBuilds returns a list of buils details, buildID is either a build ID (project:uuid) or a build ARN
+func GetCodeBuildBuilds(cfg aws.Config, buildID string) (*codebuild.BatchGetBuildsOutput, error) {
 	client := codebuild.NewFromConfig(cfg)
 
-	arnPrefix := getArnPrefix(cfg.Region, accountID, "codebuildBuild")
-	arn := fmt.Sprintf("%s%s", arnPrefix, buildID)
 	input := &codebuild.BatchGetBuildsInput{
-		Ids: []string{arn},
+		Ids: []string{buildID},
 	}
 
-	builds, err = client.BatchGetBuilds(context.TODO(), input)
-	return builds, err
+	return client.BatchGetBuilds(context.Background(), input)
 }
 
-// GetCodeBuildProjects returns a list of projects
-func GetCodeBuildProjects(cfg aws.Config, accountID, buildID string) (*codebuild.BatchGetProjectsOutput, error) {
+// GetCodeBuildProjects returns a list of projects, name is either a project name or a project ARN
+func GetCodeBuildProjects(cfg aws.Config, name string) (*codebuild.BatchGetProjectsOutput, error) {
 	client := codebuild.NewFromConfig(cfg)
 
-	arnPrefix := getArnPrefix(cfg.Region, accountID, "codebuildProject")
-	arn := fmt.Sprintf("%s%s", arnPrefix, buildID)
-
 	input := &codebuild.BatchGetProjectsInput{
-		Names: []string{arn},
+		Names: []string{name},
 	}
 
-	projects, err := client.BatchGetProjects(context.Background(), input)
-	return projects, err
+	return client.BatchGetProjects(context.Background(), input)
 }
@@ -0,0 +1,139 @@
+package awsqueries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codecommit"
+	"github.com/aws/aws-sdk-go-v2/service/codecommit/types"
+)
+
+// GetCodeCommitFile returns the content of a file stored in a CodeCommit repository at a given commit
+func GetCodeCommitFile(cfg aws.Config, repositoryName, commit, filePath string) ([]byte, error) {
+	client := codecommit.NewFromConfig(cfg)
+
+	input := &codecommit.GetFileInput{
+		RepositoryName:  aws.String(repositoryName),
+		CommitSpecifier: aws.String(commit),
+		FilePath:        aws.String(filePath),
+	}
+
+	file, err := client.GetFile(context.Background(), input)
+	if err != nil {
+		return nil, err
+	}
+	return file.FileContent, nil
+}
+
+// GetCodeCommitCommit returns the details of a CodeCommit commit
+func GetCodeCommitCommit(cfg aws.Config, repositoryName, commitID string) (*types.Commit, error) {
+	client := codecommit.NewFromConfig(cfg)
+
+	commit, err := client.GetCommit(context.Background(), &codecommit.GetCommitInput{
+		RepositoryName: aws.String(repositoryName),
+		CommitId:       aws.String(commitID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return commit.Commit, nil
+}
+
+// ListCodeCommitCommits returns the commits reachable from commitID but not from baseCommitID, the most recent first
+// CodeCommit doesn't provide a log API, history is walked one commit at a time and at most maxCommits are returned
+func ListCodeCommitCommits(cfg aws.Config, repositoryName, baseCommitID, commitID string, maxCommits int) ([]types.Commit, error) {
+	return walkCommits(func(id string) (*types.Commit, error) {
+		return GetCodeCommitCommit(cfg, repositoryName, id)
+	}, baseCommitID, commitID, maxCommits)
+}
+
+// commitWalkFactor bounds the commits fetched by a walk, as a multiple of the commits returned
+const commitWalkFactor = 4
+
+// walkedCommit is a commit queued by walkCommits, base is set when it is reachable from the base commit
+type walkedCommit struct {
+	commit *types.Commit
+	base   bool
+}
+
+// walkCommits walks the history from the base and the head commits together, the most recent commit first like git log
+// base..head; the ancestors of the base are reached before the older commits of the head, even through merge commits
+func walkCommits(fetch func(string) (*types.Commit, error), baseCommitID, commitID string, maxCommits int) ([]types.Commit, error) {
+	var commits []types.Commit
+	var queue []walkedCommit
+	fetched := map[string]*types.Commit{}
+	reachable := map[string]bool{}
+	seen := map[string]bool{}
+	heads := 0
+
+	push := func(id string, base bool) error {
+		commit, ok := fetched[id]
+		if !ok {
+			var err error
+			if commit, err = fetch(id); err != nil {
+				return err
+			}
+			fetched[id] = commit
+		}
+		queue = append(queue, walkedCommit{commit: commit, base: base})
+		if !base {
+			heads++
+		}
+		return nil
+	}
+	if err := push(commitID, false); err != nil {
+		return nil, err
+	}
+	if baseCommitID != "" {
+		if err := push(baseCommitID, true); err != nil {
+			return nil, err
+		}
+	}
+
+	// The walk stops once the commits of the head are all reachable from the base
+	for heads > 0 && len(commits) < maxCommits && len(fetched) < commitWalkFactor*maxCommits {
+		// The most recent commit first, the commits of the base before the same commits of the head
+		next := 0
+		for i, c := range queue {
+			t, nt := commitTime(c.commit), commitTime(queue[next].commit)
+			if t > nt || t == nt && c.base && !queue[next].base {
+				next = i
+			}
+		}
+		c := queue[next]
+		queue = append(queue[:next], queue[next+1:]...)
+		if !c.base {
+			heads--
+		}
+
+		id := aws.ToString(c.commit.CommitId)
+		switch {
+		case c.base:
+			if reachable[id] {
+				continue
+			}
+			reachable[id] = true
+		case reachable[id] || seen[id]:
+			continue
+		default:
+			seen[id] = true
+			commits = append(commits, *c.commit)
+		}
+		for _, parent := range c.commit.Parents {
+			if err := push(parent, c.base); err != nil {
+				return commits, err
+			}
+		}
+	}
+	return commits, nil
+}
+
+// commitTime returns the committer date of a commit in seconds, the date is formatted as "1484167798 -0800"
+func commitTime(commit *types.Commit) int64 {
+	var t int64
+	if commit.Committer != nil {
+		fmt.Sscan(aws.ToString(commit.Committer.Date), &t)
+	}
+	return t
+}
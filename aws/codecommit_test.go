@@ -0,0 +1,119 @@
+package awsqueries
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codecommit/types"
+)
+
+// testCommit is a commit of a fake history, committed at time with its parents
+type testCommit struct {
+	id      string
+	time    int
+	parents []string
+}
+
+// testHistory returns a fetch function of the commits of a fake history and the number of commits fetched
+func testHistory(commits ...testCommit) (func(string) (*types.Commit, error), *int) {
+	fetched := 0
+	history := make(map[string]*types.Commit, len(commits))
+	for _, c := range commits {
+		history[c.id] = &types.Commit{
+			CommitId:  aws.String(c.id),
+			Parents:   c.parents,
+			Committer: &types.UserInfo{Date: aws.String(fmt.Sprintf("%d -0800", c.time))},
+		}
+	}
+	return func(id string) (*types.Commit, error) {
+		fetched++
+		commit, ok := history[id]
+		if !ok {
+			return nil, fmt.Errorf("commit %v not found", id)
+		}
+		return commit, nil
+	}, &fetched
+}
+
+// linearHistory returns n commits c1..cn, each commit the parent of the next one
+func linearHistory(n int) []testCommit {
+	commits := []testCommit{{id: "c1", time: 1}}
+	for i := 2; i <= n; i++ {
+		commits = append(commits, testCommit{id: fmt.Sprint("c", i), time: i, parents: []string{fmt.Sprint("c", i-1)}})
+	}
+	return commits
+}
+
+func TestWalkCommits(t *testing.T) {
+	// a1 - b2 - c4 - m5 - n7
+	//   \ \          /
+	//    \  f3 ------
+	//     x6
+	merge := []testCommit{
+		{id: "a1", time: 1},
+		{id: "b2", time: 2, parents: []string{"a1"}},
+		{id: "f3", time: 3, parents: []string{"a1"}},
+		{id: "c4", time: 4, parents: []string{"b2"}},
+		{id: "m5", time: 5, parents: []string{"c4", "f3"}},
+		{id: "x6", time: 6, parents: []string{"a1"}},
+		{id: "n7", time: 7, parents: []string{"m5"}},
+	}
+
+	tests := []struct {
+		name       string
+		history    []testCommit
+		base, head string
+		max        int
+		want       []string
+		err        bool
+	}{
+		{name: "linear history", history: linearHistory(5), base: "c2", head: "c5", max: 10, want: []string{"c5", "c4", "c3"}},
+		{name: "no base", history: linearHistory(3), head: "c3", max: 10, want: []string{"c3", "c2", "c1"}},
+		{name: "base is the head", history: linearHistory(3), base: "c3", head: "c3", max: 10},
+		{name: "head is an ancestor of the base", history: linearHistory(3), base: "c3", head: "c2", max: 10},
+		{name: "merged branch", history: merge, base: "b2", head: "n7", max: 10, want: []string{"n7", "m5", "c4", "f3"}},
+		{name: "merged branch is deployed", history: merge, base: "m5", head: "n7", max: 10, want: []string{"n7"}},
+		{name: "merged commit is the base", history: merge, base: "f3", head: "n7", max: 10, want: []string{"n7", "m5", "c4", "b2"}},
+		{name: "base is not an ancestor", history: merge, base: "x6", head: "m5", max: 10, want: []string{"m5", "c4", "f3", "b2"}},
+		{name: "at most max commits", history: linearHistory(10), base: "c1", head: "c10", max: 3, want: []string{"c10", "c9", "c8"}},
+		{name: "missing commit", history: linearHistory(3)[1:], head: "c3", max: 10, want: []string{"c3", "c2"}, err: true},
+		{name: "missing head", history: linearHistory(3), head: "c4", max: 10, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetch, _ := testHistory(tt.history...)
+			commits, err := walkCommits(fetch, tt.base, tt.head, tt.max)
+			if (err != nil) != tt.err {
+				t.Fatalf("walkCommits(%v..%v) error = %v, want error %v", tt.base, tt.head, err, tt.err)
+			}
+			var got []string
+			for _, c := range commits {
+				got = append(got, aws.ToString(c.CommitId))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("walkCommits(%v..%v) = %v, want %v", tt.base, tt.head, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalkCommitsBound(t *testing.T) {
+	// The base is 50 commits ahead of the head on another branch, only the fetched commits are walked
+	history := linearHistory(50)
+	history = append(history, testCommit{id: "head", time: 0, parents: []string{"c1"}})
+	fetch, fetched := testHistory(history...)
+
+	maxCommits := 2
+	commits, err := walkCommits(fetch, "c50", "head", maxCommits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 0 {
+		t.Errorf("walkCommits returned %v commits, want none before the bound", len(commits))
+	}
+	if *fetched > commitWalkFactor*maxCommits {
+		t.Errorf("walkCommits fetched %v commits, want at most %v", *fetched, commitWalkFactor*maxCommits)
+	}
+}
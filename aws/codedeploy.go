@@ -0,0 +1,59 @@
+package awsqueries
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codedeploy"
+	"github.com/aws/aws-sdk-go-v2/service/codedeploy/types"
+)
+
+// CodeDeployData holds the details of a CodeDeploy deployment
+type CodeDeployData struct {
+	Deployment *types.DeploymentInfo
+	Targets    []types.DeploymentTarget
+}
+
+// GetCodeDeployData returns a CodeDeploy deployment and the status of all its targets
+func GetCodeDeployData(cfg aws.Config, deploymentID string) (CodeDeployData, error) {
+	var data CodeDeployData
+	client := codedeploy.NewFromConfig(cfg)
+
+	deployment, err := client.GetDeployment(context.Background(), &codedeploy.GetDeploymentInput{
+		DeploymentId: aws.String(deploymentID),
+	})
+	if err != nil {
+		return data, err
+	}
+	data.Deployment = deployment.DeploymentInfo
+
+	var targetIDs []string
+	input := &codedeploy.ListDeploymentTargetsInput{
+		DeploymentId: aws.String(deploymentID),
+	}
+	for {
+		page, err := client.ListDeploymentTargets(context.Background(), input)
+		if err != nil {
+			return data, err
+		}
+		targetIDs = append(targetIDs, page.TargetIds...)
+		if page.NextToken == nil {
+			break
+		}
+		input.NextToken = page.NextToken
+	}
+
+	// BatchGetDeploymentTargets is limited to 25 targets per call
+	for start := 0; start < len(targetIDs); start += 25 {
+		end := min(start+25, len(targetIDs))
+		targets, err := client.BatchGetDeploymentTargets(context.Background(), &codedeploy.BatchGetDeploymentTargetsInput{
+			DeploymentId: aws.String(deploymentID),
+			TargetIds:    targetIDs[start:end],
+		})
+		if err != nil {
+			return data, err
+		}
+		data.Targets = append(data.Targets, targets.DeploymentTargets...)
+	}
+	return data, nil
+}
@@ -2,9 +2,8 @@ package awsqueries
 
 import (
 	"context"
-	"strings"
+	"fmt"
 	"sync"
-	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/codepipeline"
@@ -21,24 +20,76 @@ type Pipeline struct {
 	ExecData            *codepipeline.ListPipelineExecutionsOutput
 	StateData           *codepipeline.GetPipelineStateOutput
 	Tags                map[string]string
+	// CurrentStage is the stage in progress or the failed stage, only set with DescribeOptions.State
+	CurrentStage string
+	// LastSuccess is the last successful execution, only set with DescribeOptions.LastSuccess
+	LastSuccess *types.PipelineExecutionSummary
+	// Error is set when the pipeline could not be fully described
+	Error string
+}
+
+// DefaultConcurrency is the number of pipelines described in parallel when no limit is configured
+const DefaultConcurrency = 8
+
+// lastSuccessExecutions is the number of executions searched for the last successful execution
+const lastSuccessExecutions = 20
+
+// DescribeOptions are the settings of the pipelines description, optional data cost additional queries per pipeline
+type DescribeOptions struct {
+	// Concurrency is the number of pipelines described in parallel
+	Concurrency int
+	// State describes the state of the stages and the current stage of the pipelines
+	State bool
+	// LastSuccess describes the last successful execution of the pipelines
+	LastSuccess bool
 }
 
 // CodePipelinesListFiltered is a function that returns a list of AWS CodePipeLine filtered by name and tags
-func CodePipelinesListFiltered(cfg aws.Config, accountID, pattern string, tags map[string]string) (map[string]Pipeline, error) {
+// At most opts.Concurrency pipelines are described in parallel
+func CodePipelinesListFiltered(cfg aws.Config, accountID string, names NameFilter, tags TagFilter, opts DescribeOptions) (map[string]Pipeline, error) {
 	client := codepipeline.NewFromConfig(cfg)
-	pipelines := map[string]Pipeline{}
 
-	totalPipelines, err := getTotalNumberOfPipelines(cfg)
-	if err != nil {
-		return nil, err
+	// Tag filters are resolved by the Resource Groups Tagging API in a few calls, the tags of the candidates are
+	// then matched locally; the scan of all pipelines is used when no tag is required or this API is not available
+	var tagged map[string]map[string]string
+	var err error
+	if filters := tags.taggingFilters(); len(filters) != 0 {
+		tagged, err = getTaggedPipelines(cfg, filters)
+		if err != nil {
+			log.Debug().Str("model", "aws").Str("func", "CodePipelinesListFiltered").Msgf("tagging API error, scanning all pipelines: %v", err)
+			tagged = nil
+		}
 	}
 
-	params := &codepipeline.ListPipelinesInput{}
-	paginator := codepipeline.NewListPipelinesPaginator(client, params)
+	var candidates []string
+	if tagged != nil {
+		for name, pipelineTags := range tagged {
+			if names.Match(name) && tags.Match(pipelineTags) {
+				candidates = append(candidates, name)
+			}
+		}
+	} else {
+		candidates, err = listPipelineNames(client, names)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	resultChan := make(chan Pipeline, totalPipelines)
-	var wg sync.WaitGroup
+	pipelines := map[string]Pipeline{}
+	for _, result := range describePipelines(client, cfg.Region, accountID, candidates, tagged, opts) {
+		// Pipelines which tags could not be read (nil Tags) are kept to report the error
+		if tagged != nil || len(tags) == 0 || result.Tags == nil || tags.Match(result.Tags) {
+			pipelines[result.PipelineName] = result
+		}
+	}
+	return pipelines, nil
+}
 
+// listPipelineNames returns the name of all the AWS CodePipelines matching the name filter
+func listPipelineNames(client *codepipeline.Client, names NameFilter) ([]string, error) {
+	var result []string
+
+	paginator := codepipeline.NewListPipelinesPaginator(client, &codepipeline.ListPipelinesInput{})
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(context.Background())
 		if err != nil {
@@ -46,116 +97,177 @@ func CodePipelinesListFiltered(cfg aws.Config, accountID, pattern string, tags m
 		}
 
 		for _, pipeline := range page.Pipelines {
-			name := *pipeline.Name
-
-			// Check if pipeline name matches the pattern
-			if pattern == "" || strings.Contains(name, pattern) {
-				wg.Add(1)
-				go getPipelineTags(client, cfg.Region, accountID, name, &wg, resultChan)
+			name := aws.ToString(pipeline.Name)
+			if names.Match(name) {
+				result = append(result, name)
 			}
 		}
-		wg.Wait()
-		close(resultChan)
+	}
+	return result, nil
+}
+
+// describePipelines describes the pipelines with a pool of concurrency workers
+// tagged holds the tags already known by pipeline name, nil when tags have to be queried
+func describePipelines(client *codepipeline.Client, region, accountID string, names []string, tagged map[string]map[string]string, opts DescribeOptions) []Pipeline {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
 
-		for result := range resultChan {
-			if len(tags) != 0 && tagsMatch(result.Tags, tags) {
-				pipelines[result.PipelineName] = result
-			} else if len(tags) == 0 {
-				pipelines[result.PipelineName] = result
+	jobs := make(chan string)
+	results := make(chan Pipeline, len(names))
+	var wg sync.WaitGroup
+
+	for i := 0; i < min(concurrency, len(names)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				var knownTags map[string]string
+				if tagged != nil {
+					knownTags = tagged[name]
+				}
+				results <- describePipeline(client, region, accountID, name, knownTags, opts)
 			}
-		}
+		}()
 	}
-	return pipelines, nil
+
+	for _, name := range names {
+		jobs <- name
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+
+	pipelines := make([]Pipeline, 0, len(names))
+	for result := range results {
+		pipelines = append(pipelines, result)
+	}
+	return pipelines
 }
 
-// getTotalNumberOfPipelines is a function that returns the total number of AWS CodePipeLine
-func getTotalNumberOfPipelines(cfg aws.Config) (int, error) {
-	client := codepipeline.NewFromConfig(cfg)
+// DescribePipelines returns the last execution and the tags of AWS CodePipeLines, at most opts.Concurrency pipelines
+// are described in parallel; tagged holds the tags already known by pipeline name, nil when tags have to be queried
+func DescribePipelines(cfg aws.Config, accountID string, names []string, tagged map[string]map[string]string, opts DescribeOptions) []Pipeline {
+	return describePipelines(codepipeline.NewFromConfig(cfg), cfg.Region, accountID, names, tagged, opts)
+}
 
-	params := &codepipeline.ListPipelinesInput{}
-	resp, err := client.ListPipelines(context.Background(), params)
-	return len(resp.Pipelines), err
+// DescribePipeline returns the last execution and the tags of a AWS CodePipeLine
+// Tags are only queried when knownTags is nil
+func DescribePipeline(cfg aws.Config, accountID, pipelineName string, knownTags map[string]string, opts DescribeOptions) Pipeline {
+	return describePipeline(codepipeline.NewFromConfig(cfg), cfg.Region, accountID, pipelineName, knownTags, opts)
 }
 
-// getPipelineTags is a function that returns the tags of a AWS CodePipeLine TODO Rename this as it returns more than just tags
-func getPipelineTags(client *codepipeline.Client, region, accountID, pipelineName string, wg *sync.WaitGroup, resultChan chan<- Pipeline) {
-	defer wg.Done()
+// describePipeline returns the last execution and the tags of a AWS CodePipeLine
+// Tags are only queried when knownTags is nil, throttling and retries are handled by the client configuration
+func describePipeline(client *codepipeline.Client, region, accountID, pipelineName string, knownTags map[string]string, opts DescribeOptions) Pipeline {
 	result := Pipeline{
-		PipelineName: pipelineName,
-		Tags:         map[string]string{},
-		Data:         nil,
+		PipelineName:        pipelineName,
+		LastExecutionStatus: "Unknown",
+		Tags:                map[string]string{},
 	}
 
-	var err error
-
-	params := &codepipeline.ListPipelineExecutionsInput{
+	maxResults := int32(1)
+	if opts.LastSuccess {
+		maxResults = lastSuccessExecutions
+	}
+	execData, err := client.ListPipelineExecutions(context.Background(), &codepipeline.ListPipelineExecutionsInput{
 		PipelineName: aws.String(pipelineName),
-		MaxResults:   aws.Int32(1),
+		MaxResults:   aws.Int32(maxResults),
+	})
+	if err != nil {
+		log.Debug().Str("model", "aws").Str("func", "describePipeline").Msgf("list executions query error for %v: %v", pipelineName, err)
+		result.Error = fmt.Sprintf("failed to list executions: %v", err)
+	} else {
+		for _, summary := range execData.PipelineExecutionSummaries {
+			if summary.Status == types.PipelineExecutionStatusSucceeded {
+				success := summary
+				result.LastSuccess = &success
+				break
+			}
+		}
+		// Only the last execution is kept
+		if len(execData.PipelineExecutionSummaries) > 1 {
+			execData.PipelineExecutionSummaries = execData.PipelineExecutionSummaries[:1]
+		}
+		result.ExecData = execData
+		if len(execData.PipelineExecutionSummaries) > 0 {
+			result.LastExecutionID = aws.ToString(execData.PipelineExecutionSummaries[0].PipelineExecutionId)
+			result.LastExecutionStatus = string(execData.PipelineExecutionSummaries[0].Status)
+		} else {
+			log.Debug().Str("model", "aws").Str("func", "describePipeline").Msgf("no executions found for %v", pipelineName)
+		}
 	}
 
-	// AWS doesn't provide any easy way to filter CodePipelines by tags
-	// so we have to get all the pipelines and then filter them locally
-	// AWS also seems to be pretty aggressive with their rate limiting
-	// This is a bit of a hack but it works
-	// Always filter by CodePipeline name first
-	// Then filter by tags
-
-	exponentialBackoff := 1
-	for i := 0; i < 10; i++ {
-		result.ExecData, err = client.ListPipelineExecutions(context.Background(), params)
+	if opts.State {
+		state, err := client.GetPipelineState(context.Background(), &codepipeline.GetPipelineStateInput{
+			Name: aws.String(pipelineName),
+		})
 		if err != nil {
-			log.Debug().Str("model", "aws").Str("func", "getPipelineTags").Msgf("list executions query error (%v/10): %v", i+1, err)
-			time.Sleep(time.Duration(exponentialBackoff) * time.Second)
-			exponentialBackoff += exponentialBackoff
+			log.Debug().Str("model", "aws").Str("func", "describePipeline").Msgf("get state query error for %v: %v", pipelineName, err)
 		} else {
-			break
+			result.StateData = state
+			result.CurrentStage = CurrentStage(state.StageStates)
 		}
 	}
-	if len(result.ExecData.PipelineExecutionSummaries) > 0 {
-		result.LastExecutionID = *result.ExecData.PipelineExecutionSummaries[0].PipelineExecutionId
-		result.LastExecutionStatus = string(result.ExecData.PipelineExecutionSummaries[0].Status)
-	} else {
-		result.LastExecutionID = ""
-		result.LastExecutionStatus = "Unknown"
-	}
 
-	if len(result.ExecData.PipelineExecutionSummaries) == 0 {
-		log.Debug().Str("model", "aws").Str("func", "getPipelineTags").Msgf("no executions found for %v", pipelineName)
+	if knownTags != nil {
+		result.Tags = knownTags
+		return result
 	}
 
-	pipelineArn := getArnPrefix(region, accountID, "codepipeline") + pipelineName
-
-	var tagsResp *codepipeline.ListTagsForResourceOutput
-	exponentialBackoff = 1
-	for i := 0; i < 10; i++ {
-		tagsResp, err = client.ListTagsForResource(context.Background(), &codepipeline.ListTagsForResourceInput{
-			ResourceArn: &pipelineArn,
-		})
-		if err != nil {
-			log.Debug().Str("model", "aws").Str("func", "getPipelineTags").Msgf("list tags query error (%v/10): %v", i+1, err)
-			time.Sleep(time.Duration(exponentialBackoff) * time.Second)
-			exponentialBackoff += exponentialBackoff
-		} else {
-			break
+	tagsResp, err := client.ListTagsForResource(context.Background(), &codepipeline.ListTagsForResourceInput{
+		ResourceArn: aws.String(pipelineArn(client, region, accountID, pipelineName)),
+	})
+	if err != nil {
+		log.Debug().Str("model", "aws").Str("func", "describePipeline").Msgf("list tags query error for %v: %v", pipelineName, err)
+		if result.Error == "" {
+			result.Error = fmt.Sprintf("failed to list tags: %v", err)
 		}
+		result.Tags = nil
+		return result
 	}
 
-	tags := map[string]string{}
 	for _, tag := range tagsResp.Tags {
-		tags[*tag.Key] = *tag.Value
+		result.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
 	}
+	return result
+}
 
-	result.Tags = tags
-	resultChan <- result
+// pipelineArn returns the ARN of a pipeline returned by the APIs, GetPipeline is only queried when the ARN is not
+// known yet; the ARN is built from the region and the account when it can't be queried
+func pipelineArn(client *codepipeline.Client, region, accountID, pipelineName string) string {
+	if arn, ok := pipelineArns.Load(pipelineArnKey(region, accountID, pipelineName)); ok {
+		return arn.(string)
+	}
+	resp, err := client.GetPipeline(context.Background(), &codepipeline.GetPipelineInput{
+		Name: aws.String(pipelineName),
+	})
+	if err != nil || resp.Metadata == nil || resp.Metadata.PipelineArn == nil {
+		log.Debug().Str("model", "aws").Str("func", "pipelineArn").Msgf("no ARN returned for %v: %v", pipelineName, err)
+		return getArnPrefix(region, accountID, "codepipeline") + pipelineName
+	}
+	cachePipelineArn(aws.ToString(resp.Metadata.PipelineArn))
+	return aws.ToString(resp.Metadata.PipelineArn)
 }
 
-func tagsMatch(actualTags, expectedTags map[string]string) bool {
-	for key, value := range expectedTags {
-		if actualValue, found := actualTags[key]; !found || actualValue != value {
-			return false
+// CurrentStage returns the stage in progress, or the failed stage when none is in progress
+func CurrentStage(stages []types.StageState) string {
+	failed := ""
+	for _, stage := range stages {
+		if stage.LatestExecution == nil {
+			continue
+		}
+		switch stage.LatestExecution.Status {
+		case types.StageExecutionStatusInProgress, types.StageExecutionStatusStopping:
+			return aws.ToString(stage.StageName)
+		case types.StageExecutionStatusFailed, types.StageExecutionStatusStopped:
+			if failed == "" {
+				failed = aws.ToString(stage.StageName)
+			}
 		}
 	}
-	return true
+	return failed
 }
 
 // GetPipelineInfo is a function that returns the information of a AWS CodePipeLine
@@ -166,6 +278,26 @@ func GetPipelineInfo(cfg aws.Config, pipelineName string) (*codepipeline.GetPipe
 		Name: aws.String(pipelineName),
 	}
 	resp, err := client.GetPipeline(context.Background(), params)
+	if err == nil && resp.Metadata != nil {
+		cachePipelineArn(aws.ToString(resp.Metadata.PipelineArn))
+	}
+	return resp, err
+}
+
+// GetPipelineVersion returns the declaration of a AWS CodePipeLine at a given version, the latest version when version is 0
+func GetPipelineVersion(cfg aws.Config, pipelineName string, version int32) (*codepipeline.GetPipelineOutput, error) {
+	client := codepipeline.NewFromConfig(cfg)
+
+	params := &codepipeline.GetPipelineInput{
+		Name: aws.String(pipelineName),
+	}
+	if version > 0 {
+		params.Version = aws.Int32(version)
+	}
+	resp, err := client.GetPipeline(context.Background(), params)
+	if err == nil && resp.Metadata != nil {
+		cachePipelineArn(aws.ToString(resp.Metadata.PipelineArn))
+	}
 	return resp, err
 }
 
@@ -204,6 +336,18 @@ func StartPipelineExecution(cfg aws.Config, pipelineName string) error {
 	return err
 }
 
+// StopPipelineExecution stops an execution of a pipeline once its actions in progress completed
+func StopPipelineExecution(cfg aws.Config, pipelineName, executionID, reason string) error {
+	client := codepipeline.NewFromConfig(cfg)
+	params := &codepipeline.StopPipelineExecutionInput{
+		PipelineName:        aws.String(pipelineName),
+		PipelineExecutionId: aws.String(executionID),
+		Reason:              aws.String(reason),
+	}
+	_, err := client.StopPipelineExecution(context.Background(), params)
+	return err
+}
+
 // DisablePipelineStageTransition is a function that disables a stage transition of a AWS CodePipeLine
 func DisablePipelineStageTransition(cfg aws.Config, pipelineName, stageName, reason string) error {
 	client := codepipeline.NewFromConfig(cfg)
@@ -228,3 +372,95 @@ func EnablePipelinStageTransition(cfg aws.Config, pipelineName, stageName string
 	_, err := client.EnableStageTransition(context.Background(), params)
 	return err
 }
+
+// GetActionExecution returns the latest execution details of an action for a given pipeline execution
+func GetActionExecution(cfg aws.Config, pipelineName, pipelineExecutionID, stageName, actionName string) (*types.ActionExecutionDetail, error) {
+	details, err := ListActionExecutions(cfg, pipelineName, pipelineExecutionID)
+	if err != nil {
+		return nil, err
+	}
+	for _, detail := range details {
+		if aws.ToString(detail.StageName) == stageName && aws.ToString(detail.ActionName) == actionName {
+			return &detail, nil
+		}
+	}
+	return nil, fmt.Errorf("no execution found for action %v in stage %v", actionName, stageName)
+}
+
+// ListActionExecutions returns the action executions of a pipeline execution, from the most recent to the oldest
+func ListActionExecutions(cfg aws.Config, pipelineName, pipelineExecutionID string) ([]types.ActionExecutionDetail, error) {
+	client := codepipeline.NewFromConfig(cfg)
+
+	params := &codepipeline.ListActionExecutionsInput{
+		PipelineName: aws.String(pipelineName),
+		Filter: &types.ActionExecutionFilter{
+			PipelineExecutionId: aws.String(pipelineExecutionID),
+		},
+	}
+	paginator := codepipeline.NewListActionExecutionsPaginator(client, params)
+
+	var details []types.ActionExecutionDetail
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return details, err
+		}
+		details = append(details, page.ActionExecutionDetails...)
+	}
+	return details, nil
+}
+
+// GetPipelineExecution returns an execution of a AWS CodePipeLine, including its resolved variables
+func GetPipelineExecution(cfg aws.Config, pipelineName, pipelineExecutionID string) (*types.PipelineExecution, error) {
+	client := codepipeline.NewFromConfig(cfg)
+
+	params := &codepipeline.GetPipelineExecutionInput{
+		PipelineName:        aws.String(pipelineName),
+		PipelineExecutionId: aws.String(pipelineExecutionID),
+	}
+	resp, err := client.GetPipelineExecution(context.Background(), params)
+	if err != nil {
+		return nil, err
+	}
+	return resp.PipelineExecution, nil
+}
+
+// PutApprovalResult is a function that approves or rejects a manual approval action of a AWS CodePipeLine
+func PutApprovalResult(cfg aws.Config, pipelineName, stageName, actionName, token string, status types.ApprovalStatus, summary string) error {
+	client := codepipeline.NewFromConfig(cfg)
+	params := &codepipeline.PutApprovalResultInput{
+		PipelineName: aws.String(pipelineName),
+		StageName:    aws.String(stageName),
+		ActionName:   aws.String(actionName),
+		Token:        aws.String(token),
+		Result: &types.ApprovalResult{
+			Status:  status,
+			Summary: aws.String(summary),
+		},
+	}
+	_, err := client.PutApprovalResult(context.Background(), params)
+	return err
+}
+
+// ListPipelineExecutions is a function that returns up to maxResults executions of a AWS CodePipeLine, most recent first
+func ListPipelineExecutions(cfg aws.Config, pipelineName string, maxResults int) ([]types.PipelineExecutionSummary, error) {
+	client := codepipeline.NewFromConfig(cfg)
+
+	var executions []types.PipelineExecutionSummary
+	params := &codepipeline.ListPipelineExecutionsInput{
+		PipelineName: aws.String(pipelineName),
+	}
+	paginator := codepipeline.NewListPipelineExecutionsPaginator(client, params)
+
+	for paginator.HasMorePages() && len(executions) < maxResults {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return executions, err
+		}
+		executions = append(executions, page.PipelineExecutionSummaries...)
+	}
+	if len(executions) > maxResults {
+		executions = executions[:maxResults]
+	}
+	return executions, nil
+}
@@ -3,8 +3,11 @@ package awsqueries
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/rs/zerolog/log"
 )
@@ -19,16 +22,61 @@ func GetAwsAccountID(cfg aws.Config) string {
 	return *accountID.Account
 }
 
+// Partition returns the AWS partition of a region, as resolved by the endpoints of the SDK
+func Partition(region string) string {
+	endpoint, err := codepipeline.NewDefaultEndpointResolver().ResolveEndpoint(region, codepipeline.EndpointResolverOptions{})
+	if err != nil || endpoint.PartitionID == "" {
+		log.Debug().Str("model", "aws").Str("func", "Partition").Msgf("no partition resolved for %v: %v", region, err)
+		return "aws"
+	}
+	return endpoint.PartitionID
+}
+
+// ConsoleURL returns the URL of a page of the AWS console of a region, path starts with a /
+func ConsoleURL(region, path string) string {
+	var domain string
+	switch Partition(region) {
+	case "aws-us-gov":
+		domain = "console.amazonaws-us-gov.com"
+	case "aws-cn":
+		domain = "console.amazonaws.cn"
+	case "aws-iso":
+		domain = "console.c2s.ic.gov"
+	case "aws-iso-b":
+		domain = "console.sc2s.sgov.gov"
+	case "aws-iso-e":
+		domain = "console.cloud.adc-e.uk"
+	case "aws-iso-f":
+		domain = "console.csp.hci.ic.gov"
+	default:
+		domain = "console.aws.amazon.com"
+	}
+	return fmt.Sprintf("https://%v.%v%v", region, domain, path)
+}
+
+// pipelineArns holds the ARNs returned by the APIs, indexed by pipelineArnKey
+var pipelineArns sync.Map
+
+// pipelineArnKey returns the key of the ARN of a pipeline in pipelineArns
+func pipelineArnKey(region, accountID, pipelineName string) string {
+	return region + ":" + accountID + ":" + pipelineName
+}
+
+// cachePipelineArn keeps an ARN of a pipeline returned by the APIs: arn:partition:codepipeline:region:account:name
+func cachePipelineArn(arn string) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 {
+		return
+	}
+	pipelineArns.Store(pipelineArnKey(parts[3], parts[4], parts[5]), arn)
+}
+
 // Little helper to get the ARN prefix for a given endpoint
-// This save a lots of API calls to AWS, but this won't support special cases like gov-cloud at the moment
+// This save a lots of API calls to AWS, ARNs returned by the APIs should be preferred when available
 func getArnPrefix(region, accountID, endpoint string) string {
 	switch endpoint {
-	case "codebuildBuild":
-		return fmt.Sprintf("arn:aws:codebuild:%v:%v:build/", region, accountID)
-	case "codebuildProject":
-		return fmt.Sprintf("arn:aws:codebuild:%v:%v:project/", region, accountID)
 	case "codepipeline":
-		return fmt.Sprintf("arn:aws:codepipeline:%v:%v:", region, accountID)
+		return fmt.Sprintf("arn:%v:codepipeline:%v:%v:", Partition(region), region, accountID)
 	default:
 		return ""
 	}
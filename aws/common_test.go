@@ -0,0 +1,43 @@
+package awsqueries
+
+import "testing"
+
+func TestPartition(t *testing.T) {
+	tests := []struct {
+		region    string
+		partition string
+		console   string
+	}{
+		{"us-east-1", "aws", "https://us-east-1.console.aws.amazon.com/codesuite"},
+		{"us-gov-west-1", "aws-us-gov", "https://us-gov-west-1.console.amazonaws-us-gov.com/codesuite"},
+		{"cn-north-1", "aws-cn", "https://cn-north-1.console.amazonaws.cn/codesuite"},
+		{"us-iso-east-1", "aws-iso", "https://us-iso-east-1.console.c2s.ic.gov/codesuite"},
+		{"us-isob-east-1", "aws-iso-b", "https://us-isob-east-1.console.sc2s.sgov.gov/codesuite"},
+		{"eu-isoe-west-1", "aws-iso-e", "https://eu-isoe-west-1.console.cloud.adc-e.uk/codesuite"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			if got := Partition(tt.region); got != tt.partition {
+				t.Errorf("Partition(%q) = %q, want %q", tt.region, got, tt.partition)
+			}
+			if got := ConsoleURL(tt.region, "/codesuite"); got != tt.console {
+				t.Errorf("ConsoleURL(%q) = %q, want %q", tt.region, got, tt.console)
+			}
+		})
+	}
+}
+
+func TestCachePipelineArn(t *testing.T) {
+	arn := "arn:aws-us-gov:codepipeline:us-gov-west-1:123456789012:my-pipeline"
+	cachePipelineArn(arn)
+	t.Cleanup(func() { pipelineArns.Delete(pipelineArnKey("us-gov-west-1", "123456789012", "my-pipeline")) })
+
+	got, ok := pipelineArns.Load(pipelineArnKey("us-gov-west-1", "123456789012", "my-pipeline"))
+	if !ok || got != arn {
+		t.Errorf("cached ARN = %v, want %v", got, arn)
+	}
+	cachePipelineArn("not-an-arn")
+	if _, ok := pipelineArns.Load(pipelineArnKey("", "", "not-an-arn")); ok {
+		t.Error("an invalid ARN was cached")
+	}
+}
@@ -0,0 +1,61 @@
+package awsqueries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// ECSServiceData holds the details of an ECS service deployed by a CodePipeline action
+type ECSServiceData struct {
+	Service *types.Service
+	Tasks   []types.Task
+}
+
+// GetECSServiceData returns an ECS service with its deployments, events and tasks
+func GetECSServiceData(cfg aws.Config, clusterName, serviceName string) (ECSServiceData, error) {
+	var data ECSServiceData
+	client := ecs.NewFromConfig(cfg)
+
+	services, err := client.DescribeServices(context.Background(), &ecs.DescribeServicesInput{
+		Cluster:  aws.String(clusterName),
+		Services: []string{serviceName},
+	})
+	if err != nil {
+		return data, err
+	}
+	if len(services.Services) != 1 {
+		return data, fmt.Errorf("ECS service %v not found in cluster %v", serviceName, clusterName)
+	}
+	data.Service = &services.Services[0]
+
+	var taskArns []string
+	paginator := ecs.NewListTasksPaginator(client, &ecs.ListTasksInput{
+		Cluster:     aws.String(clusterName),
+		ServiceName: aws.String(serviceName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return data, err
+		}
+		taskArns = append(taskArns, page.TaskArns...)
+	}
+
+	// DescribeTasks is limited to 100 tasks per call
+	for start := 0; start < len(taskArns); start += 100 {
+		end := min(start+100, len(taskArns))
+		tasks, err := client.DescribeTasks(context.Background(), &ecs.DescribeTasksInput{
+			Cluster: aws.String(clusterName),
+			Tasks:   taskArns[start:end],
+		})
+		if err != nil {
+			return data, err
+		}
+		data.Tasks = append(data.Tasks, tasks.Tasks...)
+	}
+	return data, nil
+}
@@ -0,0 +1,64 @@
+package awsqueries
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// endpointAliases are the short names accepted for services with long identifiers
+var endpointAliases = map[string]string{
+	"logs":    "cloudwatchlogs",
+	"tagging": "resourcegroupstaggingapi",
+}
+
+// SetEndpoints overrides the endpoints of every client created from cfg, and its copies
+// global applies to all the services, services overrides it by service name: codepipeline, codebuild, logs, sts...
+// Hostnames are left unchanged, the S3 clients address the buckets with path-style requests as expected by LocalStack and MinIO
+func SetEndpoints(cfg *aws.Config, global string, services map[string]string) {
+	if global == "" && len(services) == 0 {
+		return
+	}
+
+	endpoints := make(map[string]string, len(services))
+	for service, url := range services {
+		service = normalizeServiceID(service)
+		if alias, ok := endpointAliases[service]; ok {
+			service = alias
+		}
+		endpoints[service] = url
+	}
+
+	// The deprecated per-service resolver is the only way to override the endpoints of all the clients of a configuration
+	cfg.EndpointResolverWithOptions = aws.EndpointResolverWithOptionsFunc(func(service, region string, _ ...interface{}) (aws.Endpoint, error) {
+		url, ok := endpoints[normalizeServiceID(service)]
+		if !ok {
+			url = global
+		}
+		if url == "" {
+			// Fallback to the default endpoint resolution
+			return aws.Endpoint{}, &aws.EndpointNotFoundError{}
+		}
+
+		return aws.Endpoint{
+			URL:               url,
+			SigningRegion:     region,
+			HostnameImmutable: true,
+			Source:            aws.EndpointSourceCustom,
+		}, nil
+	})
+}
+
+// customEndpoint returns true when the endpoint of a service is overridden by SetEndpoints
+func customEndpoint(cfg aws.Config, service string) bool {
+	if cfg.EndpointResolverWithOptions == nil {
+		return false
+	}
+	_, err := cfg.EndpointResolverWithOptions.ResolveEndpoint(service, cfg.Region)
+	return err == nil
+}
+
+// normalizeServiceID returns the lower case service ID without spaces: "CloudWatch Logs" => "cloudwatchlogs"
+func normalizeServiceID(service string) string {
+	return strings.ToLower(strings.ReplaceAll(service, " ", ""))
+}
@@ -0,0 +1,249 @@
+package awsqueries
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+)
+
+// pattern matches a value with a regular expression, or with a string when re is nil
+type pattern struct {
+	value     string
+	substring bool
+	re        *regexp.Regexp
+}
+
+// newPattern returns a pattern, a ~ prefix is a regular expression and values with *, ? or [ are globs
+// Other values are matched as a substring, or exactly when substring is false
+func newPattern(value string, substring bool) (pattern, error) {
+	switch {
+	case strings.HasPrefix(value, "~"):
+		re, err := regexp.Compile(value[1:])
+		if err != nil {
+			return pattern{}, fmt.Errorf("invalid regular expression %q: %w", value[1:], err)
+		}
+		return pattern{value: value, re: re}, nil
+	case strings.ContainsAny(value, "*?["):
+		re, err := regexp.Compile(globRegexp(value))
+		if err != nil {
+			return pattern{}, fmt.Errorf("invalid glob %q: %w", value, err)
+		}
+		return pattern{value: value, re: re}, nil
+	default:
+		return pattern{value: value, substring: substring}, nil
+	}
+}
+
+func (p pattern) match(s string) bool {
+	switch {
+	case p.re != nil:
+		return p.re.MatchString(s)
+	case p.substring:
+		return strings.Contains(s, p.value)
+	default:
+		return s == p.value
+	}
+}
+
+// globRegexp returns the regular expression of a glob matching the whole value: * is any string, ? any character
+// and [...] a character class, [!...] is negated
+func globRegexp(glob string) string {
+	var re strings.Builder
+	re.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			re.WriteString(".*")
+		case '?':
+			re.WriteString(".")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				re.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			re.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			re.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	re.WriteString("$")
+	return re.String()
+}
+
+// NameFilter selects the AWS CodePipelines by name
+// A name matches when it matches one of the include patterns, or there are none, and none of the exclude patterns
+// Patterns are substrings, globs when they contain *, ? or [, or regular expressions prefixed with ~
+type NameFilter struct {
+	include, exclude []pattern
+}
+
+// NewNameFilter returns the filter of the include and exclude patterns
+func NewNameFilter(include, exclude []string) (NameFilter, error) {
+	var f NameFilter
+	for _, p := range include {
+		if p == "" {
+			continue
+		}
+		compiled, err := newPattern(p, true)
+		if err != nil {
+			return NameFilter{}, err
+		}
+		f.include = append(f.include, compiled)
+	}
+	for _, p := range exclude {
+		if p == "" {
+			continue
+		}
+		compiled, err := newPattern(p, true)
+		if err != nil {
+			return NameFilter{}, err
+		}
+		f.exclude = append(f.exclude, compiled)
+	}
+	return f, nil
+}
+
+// Match returns true when the name is selected by the filter
+func (f NameFilter) Match(name string) bool {
+	for _, p := range f.exclude {
+		if p.match(name) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, p := range f.include {
+		if p.match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f NameFilter) String() string {
+	values := func(patterns []pattern) []string {
+		s := make([]string, len(patterns))
+		for i, p := range patterns {
+			s[i] = p.value
+		}
+		return s
+	}
+	return fmt.Sprintf("include: %v, exclude: %v", values(f.include), values(f.exclude))
+}
+
+// tagMissing is the tag value selecting the pipelines without the tag, * selects the pipelines with the tag
+const tagMissing = "!*"
+
+// tagCondition selects the pipelines by the value of a tag
+type tagCondition struct {
+	key     string
+	values  []pattern
+	missing bool // pipelines without the tag match
+}
+
+// TagFilter selects the AWS CodePipelines by tags, the pipelines must match all the tag conditions
+// A tag matches one of its values: exact values, globs when they contain *, ? or [, or regular expressions
+// prefixed with ~; * matches any value and !* the pipelines without the tag
+type TagFilter []tagCondition
+
+// NewTagFilter returns the filter of the values of each tag
+func NewTagFilter(tags map[string][]string) (TagFilter, error) {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	f := make(TagFilter, 0, len(keys))
+	for _, key := range keys {
+		c := tagCondition{key: key}
+		for _, value := range tags[key] {
+			if value == tagMissing {
+				c.missing = true
+				continue
+			}
+			p, err := newPattern(value, false)
+			if err != nil {
+				return nil, fmt.Errorf("tag %v: %w", key, err)
+			}
+			c.values = append(c.values, p)
+		}
+		if len(c.values) == 0 && !c.missing {
+			return nil, fmt.Errorf("tag %v: missing value, use * to match any value", key)
+		}
+		f = append(f, c)
+	}
+	return f, nil
+}
+
+// Match returns true when the tags match all the tag conditions
+func (f TagFilter) Match(tags map[string]string) bool {
+	for _, c := range f {
+		value, found := tags[c.key]
+		if !found {
+			if !c.missing {
+				return false
+			}
+			continue
+		}
+		matched := false
+		for _, p := range c.values {
+			if p.match(value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func (f TagFilter) String() string {
+	s := make([]string, len(f))
+	for i, c := range f {
+		var values []string
+		for _, p := range c.values {
+			values = append(values, p.value)
+		}
+		if c.missing {
+			values = append(values, tagMissing)
+		}
+		s[i] = c.key + "=" + strings.Join(values, "|")
+	}
+	return strings.Join(s, ",")
+}
+
+// taggingFilters returns the filters resolved by the Resource Groups Tagging API, the pipelines returned by the
+// API are a superset of the pipelines matching the filter
+// Tags which can be missing are skipped, globs and regular expressions only require the tag
+func (f TagFilter) taggingFilters() []types.TagFilter {
+	var filters []types.TagFilter
+	for _, c := range f {
+		if c.missing {
+			continue
+		}
+		filter := types.TagFilter{Key: aws.String(c.key)}
+		for _, p := range c.values {
+			if p.re != nil {
+				filter.Values = nil
+				break
+			}
+			filter.Values = append(filter.Values, p.value)
+		}
+		filters = append(filters, filter)
+	}
+	return filters
+}
@@ -0,0 +1,139 @@
+package awsqueries
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestGlobRegexp(t *testing.T) {
+	tests := []struct {
+		glob    string
+		want    string
+		matches []string
+		misses  []string
+	}{
+		{"svc-*", `^svc-.*$`, []string{"svc-", "svc-api-prod"}, []string{"my-svc-api"}},
+		{"svc-?-prod", `^svc-.-prod$`, []string{"svc-a-prod"}, []string{"svc--prod", "svc-ab-prod"}},
+		{"*-[dp]*", `^.*-[dp].*$`, []string{"api-dev", "api-prod"}, []string{"api-test"}},
+		{"*-[!dp]*", `^.*-[^dp].*$`, []string{"api-test"}, []string{"api-dev", "api-prod"}},
+		{"svc.api+", `^svc\.api\+$`, nil, []string{"svcxapi", "svc.apii"}},
+		{"svc-[api", `^svc-\[api$`, []string{"svc-[api"}, []string{"svc-a"}},
+		{`[\]x`, `^[\\]x$`, []string{`\x`}, []string{"x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.glob, func(t *testing.T) {
+			got := globRegexp(tt.glob)
+			if got != tt.want {
+				t.Fatalf("globRegexp(%q) = %q, want %q", tt.glob, got, tt.want)
+			}
+			re := regexp.MustCompile(got)
+			for _, s := range tt.matches {
+				if !re.MatchString(s) {
+					t.Errorf("glob %q does not match %q", tt.glob, s)
+				}
+			}
+			for _, s := range tt.misses {
+				if re.MatchString(s) {
+					t.Errorf("glob %q matches %q", tt.glob, s)
+				}
+			}
+		})
+	}
+}
+
+func TestNameFilter(t *testing.T) {
+	names := []string{"svc-api-prod", "svc-api-dev", "svc-web-prod", "batch-prod"}
+	tests := []struct {
+		name             string
+		include, exclude []string
+		want             []string
+	}{
+		{"no patterns", nil, nil, names},
+		{"empty patterns", []string{""}, []string{""}, names},
+		{"substring", []string{"api"}, nil, []string{"svc-api-prod", "svc-api-dev"}},
+		{"glob matches the whole name", []string{"svc-*"}, nil, []string{"svc-api-prod", "svc-api-dev", "svc-web-prod"}},
+		{"glob without wildcard at the end", []string{"*-prod"}, nil, []string{"svc-api-prod", "svc-web-prod", "batch-prod"}},
+		{"regular expression", []string{"~^svc-(api|web)-prod$"}, nil, []string{"svc-api-prod", "svc-web-prod"}},
+		{"regular expression is case sensitive", []string{"~^SVC"}, nil, nil},
+		{"any include pattern", []string{"batch", "web"}, nil, []string{"svc-web-prod", "batch-prod"}},
+		{"exclude", nil, []string{"*-dev"}, []string{"svc-api-prod", "svc-web-prod", "batch-prod"}},
+		{"exclude wins", []string{"svc-*"}, []string{"~prod$"}, []string{"svc-api-dev"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewNameFilter(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("NewNameFilter(%q, %q): %v", tt.include, tt.exclude, err)
+			}
+			var got []string
+			for _, name := range names {
+				if f.Match(name) {
+					got = append(got, name)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("%v matched %v, want %v", f, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameFilterErrors(t *testing.T) {
+	tests := []struct {
+		include, exclude []string
+	}{
+		{[]string{"~svc-(api"}, nil},
+		{nil, []string{"~[a-"}},
+		{[]string{"svc-[z-a]"}, nil},
+	}
+	for _, tt := range tests {
+		if _, err := NewNameFilter(tt.include, tt.exclude); err == nil {
+			t.Errorf("NewNameFilter(%q, %q) succeeded, want an error", tt.include, tt.exclude)
+		}
+	}
+}
+
+func TestTagFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter map[string][]string
+		tags   map[string]string
+		want   bool
+	}{
+		{"exact value", map[string][]string{"env": {"prod"}}, map[string]string{"env": "prod"}, true},
+		{"values are not substrings", map[string][]string{"env": {"prod"}}, map[string]string{"env": "preprod"}, false},
+		{"any value", map[string][]string{"env": {"dev", "prod"}}, map[string]string{"env": "dev"}, true},
+		{"glob", map[string][]string{"team": {"plat*"}}, map[string]string{"team": "platform"}, true},
+		{"regular expression", map[string][]string{"team": {"~^(web|api)$"}}, map[string]string{"team": "api"}, true},
+		{"any value of a tag", map[string][]string{"team": {"*"}}, map[string]string{"team": "web"}, true},
+		{"missing tag", map[string][]string{"team": {"*"}}, map[string]string{"env": "prod"}, false},
+		{"tag must be missing", map[string][]string{"team": {tagMissing}}, map[string]string{"env": "prod"}, true},
+		{"tag is not missing", map[string][]string{"team": {tagMissing}}, map[string]string{"team": "web"}, false},
+		{"value or missing", map[string][]string{"team": {"web", tagMissing}}, nil, true},
+		{"all tags", map[string][]string{"env": {"prod"}, "team": {"web"}}, map[string]string{"env": "prod", "team": "api"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewTagFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("NewTagFilter(%v): %v", tt.filter, err)
+			}
+			if got := f.Match(tt.tags); got != tt.want {
+				t.Errorf("%v matches %v = %v, want %v", f, tt.tags, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagFilterErrors(t *testing.T) {
+	tests := []map[string][]string{
+		{"team": {}},
+		{"team": {"~(web"}},
+	}
+	for _, filter := range tests {
+		if _, err := NewTagFilter(filter); err == nil {
+			t.Errorf("NewTagFilter(%v) succeeded, want an error", filter)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package awsqueries
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+// GetLambdaFunction returns the configuration of a Lambda function
+func GetLambdaFunction(cfg aws.Config, functionName string) (*types.FunctionConfiguration, error) {
+	client := lambda.NewFromConfig(cfg)
+
+	function, err := client.GetFunction(context.Background(), &lambda.GetFunctionInput{
+		FunctionName: aws.String(functionName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return function.Configuration, nil
+}
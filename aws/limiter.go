@@ -0,0 +1,29 @@
+package awsqueries
+
+import (
+	"context"
+	"math"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go/middleware"
+	"golang.org/x/time/rate"
+)
+
+// Throttle limits the requests sent by every client created from cfg, and its copies, to requestsPerSecond
+// All the clients share the same token bucket, retries included, so a burst of queries can't trigger the AWS rate limiting
+func Throttle(cfg *aws.Config, requestsPerSecond float64) {
+	if requestsPerSecond <= 0 {
+		return
+	}
+
+	limiter := rate.NewLimiter(rate.Limit(requestsPerSecond), int(math.Max(1, math.Ceil(requestsPerSecond))))
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("CodeplumberRateLimiter",
+			func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				if err := limiter.Wait(ctx); err != nil {
+					return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+				}
+				return next.HandleFinalize(ctx, in)
+			}), middleware.After)
+	})
+}
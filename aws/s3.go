@@ -0,0 +1,163 @@
+package awsqueries
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// newS3Client returns an S3 client, buckets are addressed with path-style requests on custom endpoints
+func newS3Client(cfg aws.Config) *s3.Client {
+	return s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.UsePathStyle = customEndpoint(cfg, s3.ServiceID)
+	})
+}
+
+// ParseS3Location returns the bucket and key of an S3 location
+// Both S3 ARNs (arn:aws:s3:::bucket/key) and plain bucket/key locations are supported
+func ParseS3Location(location string) (bucket, key string, err error) {
+	if strings.HasPrefix(location, "arn:") {
+		parts := strings.SplitN(location, ":", 6)
+		if len(parts) != 6 || parts[2] != "s3" {
+			return "", "", fmt.Errorf("invalid S3 ARN: %s", location)
+		}
+		location = parts[5]
+	}
+	location = strings.TrimPrefix(location, "s3://")
+
+	bucket, key, found := strings.Cut(location, "/")
+	if !found || bucket == "" || key == "" {
+		return "", "", fmt.Errorf("invalid S3 location: %s", location)
+	}
+	return bucket, key, nil
+}
+
+// GetS3Object returns the content of an S3 object, version is optional
+func GetS3Object(cfg aws.Config, bucket, key, version string) ([]byte, error) {
+	body, err := getS3ObjectBody(cfg, bucket, key, version)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// getS3ObjectBody returns the body of an S3 object to be read by the caller, version is optional
+func getS3ObjectBody(cfg aws.Config, bucket, key, version string) (io.ReadCloser, error) {
+	client := newS3Client(cfg)
+
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if version != "" {
+		input.VersionId = aws.String(version)
+	}
+
+	object, err := client.GetObject(context.Background(), input)
+	if err != nil {
+		return nil, err
+	}
+	return object.Body, nil
+}
+
+// s3Archive is a zip archive hosted on S3 and downloaded to a temporary file, which is removed when it is closed
+type s3Archive struct {
+	*zip.ReadCloser
+	path string
+}
+
+// openS3Archive downloads a zip archive hosted on S3 to a temporary file, artifacts can be larger than the memory
+func openS3Archive(cfg aws.Config, bucket, key, version string) (*s3Archive, error) {
+	body, err := getS3ObjectBody(cfg, bucket, key, version)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	f, err := os.CreateTemp("", "codeplumber-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(f, body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	reader, err := zip.OpenReader(f.Name())
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return &s3Archive{ReadCloser: reader, path: f.Name()}, nil
+}
+
+func (a *s3Archive) Close() error {
+	err := a.ReadCloser.Close()
+	os.Remove(a.path)
+	return err
+}
+
+// GetS3ArchiveFile returns the content of a file stored in a zip archive hosted on S3
+// This is how CodePipeline stores artifacts, and how S3 sources are provided to CodeBuild
+func GetS3ArchiveFile(cfg aws.Config, bucket, key, version, filePath string) ([]byte, error) {
+	archive, err := openS3Archive(cfg, bucket, key, version)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+	return readZipFile(&archive.Reader, filePath)
+}
+
+func readZipFile(reader *zip.Reader, filePath string) ([]byte, error) {
+	filePath = path.Clean(strings.TrimPrefix(filePath, "./"))
+	for _, f := range reader.File {
+		if path.Clean(f.Name) != filePath {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	return nil, fmt.Errorf("file %s not found in archive", filePath)
+}
+
+// HeadS3Object returns the metadata of an S3 object
+func HeadS3Object(cfg aws.Config, bucket, key string) (*s3.HeadObjectOutput, error) {
+	client := newS3Client(cfg)
+
+	return client.HeadObject(context.Background(), &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+}
+
+// ListS3Objects returns up to maxKeys objects stored under a prefix
+func ListS3Objects(cfg aws.Config, bucket, prefix string, maxKeys int32) ([]types.Object, error) {
+	client := newS3Client(cfg)
+
+	objects, err := client.ListObjectsV2(context.Background(), &s3.ListObjectsV2Input{
+		Bucket:  aws.String(bucket),
+		Prefix:  aws.String(prefix),
+		MaxKeys: aws.Int32(maxKeys),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects.Contents, nil
+}
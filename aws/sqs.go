@@ -0,0 +1,35 @@
+package awsqueries
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// ReceiveSQSMessages waits up to waitSeconds for messages of an SQS queue
+func ReceiveSQSMessages(ctx context.Context, cfg aws.Config, queueURL string, waitSeconds int32) ([]types.Message, error) {
+	client := sqs.NewFromConfig(cfg)
+
+	resp, err := client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(queueURL),
+		MaxNumberOfMessages: 10,
+		WaitTimeSeconds:     waitSeconds,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Messages, nil
+}
+
+// DeleteSQSMessage removes a processed message from an SQS queue
+func DeleteSQSMessage(ctx context.Context, cfg aws.Config, queueURL, receiptHandle string) error {
+	client := sqs.NewFromConfig(cfg)
+
+	_, err := client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(queueURL),
+		ReceiptHandle: aws.String(receiptHandle),
+	})
+	return err
+}
@@ -0,0 +1,46 @@
+package awsqueries
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+)
+
+const pipelineResourceType = "codepipeline:pipeline"
+
+// getTaggedPipelines returns the tags of the AWS CodePipelines matching all the tag filters, indexed by pipeline name
+func getTaggedPipelines(cfg aws.Config, filters []types.TagFilter) (map[string]map[string]string, error) {
+	client := resourcegroupstaggingapi.NewFromConfig(cfg)
+
+	params := &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: []string{pipelineResourceType},
+		TagFilters:          filters,
+	}
+	paginator := resourcegroupstaggingapi.NewGetResourcesPaginator(client, params)
+
+	pipelines := map[string]map[string]string{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, resource := range page.ResourceTagMappingList {
+			pipelineTags := map[string]string{}
+			for _, tag := range resource.Tags {
+				pipelineTags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			cachePipelineArn(aws.ToString(resource.ResourceARN))
+			pipelines[pipelineNameFromArn(aws.ToString(resource.ResourceARN))] = pipelineTags
+		}
+	}
+	return pipelines, nil
+}
+
+// pipelineNameFromArn returns the name of a pipeline from its ARN: arn:aws:codepipeline:region:account:name
+func pipelineNameFromArn(arn string) string {
+	return arn[strings.LastIndex(arn, ":")+1:]
+}
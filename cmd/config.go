@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"fmt"
-	"maps"
 	"os"
 	"strings"
+	"time"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+	"github.com/fabio42/codeplumber/notify"
+	"github.com/fabio42/codeplumber/tui"
 
 	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/providers/file"
@@ -14,25 +18,112 @@ import (
 
 var k = koanf.New(".")
 
+const (
+	// defaultCacheTTL is the maximum age of the cached CodePipelines displayed on startup
+	defaultCacheTTL = 24 * time.Hour
+	// defaultPollInterval is the refresh interval of the active view when no event source is configured
+	defaultPollInterval = time.Minute
+)
+
 // NEW
 func loadProfile(profile string) error {
 
 	if k.Exists("profiles." + profile) {
 		rootFlags.awsProfile = k.String("profiles." + profile + ".aws.profile")
 		rootFlags.awsRegion = k.String("profiles." + profile + ".aws.region")
+		// The flags of the command line take precedence over the profile
+		flags := rootCmd.PersistentFlags()
+		if k.Exists("profiles."+profile+".aws.endpoint") && !flags.Changed("endpoint-url") {
+			rootFlags.endpoint = k.String("profiles." + profile + ".aws.endpoint")
+		}
+		rootFlags.endpoints = k.StringMap("profiles." + profile + ".aws.endpoints")
+		if k.Exists("profiles."+profile+".aws.concurrency") && !flags.Changed("concurrency") {
+			rootFlags.concurrency = k.Int("profiles." + profile + ".aws.concurrency")
+		}
+		if k.Exists("profiles."+profile+".aws.rateLimit") && !flags.Changed("rate-limit") {
+			rootFlags.rateLimit = k.Float64("profiles." + profile + ".aws.rateLimit")
+		}
 
-		if rootFlags.tagsFilter == nil {
-			rootFlags.tagsFilter = make(map[string]string)
+		if err := loadFilters("profiles." + profile + ".filters"); err != nil {
+			return fmt.Errorf("profile %s: %w", profile, err)
+		}
+		loadEvents("profiles." + profile + ".events")
+		if err := loadColumns("profiles." + profile + "."); err != nil {
+			return fmt.Errorf("profile %s: %w", profile, err)
+		}
+		if err := loadLayout("profiles." + profile + "."); err != nil {
+			return fmt.Errorf("profile %s: %w", profile, err)
+		}
+		if err := loadNotifications("profiles." + profile + ".notifications"); err != nil {
+			return fmt.Errorf("profile %s: %w", profile, err)
 		}
-		maps.Copy(rootFlags.tagsFilter, k.StringMap("profiles."+profile+".filters.tags"))
-		rootFlags.nameFilter = k.String("profiles." + profile + ".filters.name")
 	} else {
 		return fmt.Errorf("Profile %s does not exist in config file", profile)
 	}
 	return nil
 }
 
+// loadFilters loads the name and tags filters of a profile
+// The name and exclude patterns and the values of each tag are either a single value or a list
+func loadFilters(path string) error {
+	var err error
+	if rootFlags.nameFilter, err = stringList(path + ".name"); err != nil {
+		return err
+	}
+	if rootFlags.nameExclude, err = stringList(path + ".exclude"); err != nil {
+		return err
+	}
+
+	if rootFlags.tagsFilter == nil {
+		rootFlags.tagsFilter = make(map[string][]string)
+	}
+	for _, key := range k.MapKeys(path + ".tags") {
+		if rootFlags.tagsFilter[key], err = stringList(path + ".tags." + key); err != nil {
+			return fmt.Errorf("tag %v: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// stringList returns a single value or a list of values
+func stringList(path string) ([]string, error) {
+	return values(path, k.Get(path))
+}
+
+// values returns the values of a single value or a list of values
+func values(path string, value interface{}) ([]string, error) {
+	switch value := value.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		values := make([]string, len(value))
+		for i, v := range value {
+			values[i] = fmt.Sprint(v)
+		}
+		return values, nil
+	case map[string]interface{}:
+		return nil, fmt.Errorf("%v must be a value or a list", path)
+	default:
+		return []string{fmt.Sprint(value)}, nil
+	}
+}
+
+// loadEvents loads the event source settings, a profile can override the global settings
+func loadEvents(path string) {
+	if !k.Exists(path) {
+		return
+	}
+	rootFlags.eventsSQS = k.String(path + ".sqs")
+	rootFlags.eventsHTTP = k.String(path + ".http")
+	if k.Exists(path + ".poll") {
+		rootFlags.pollInterval = k.Duration(path + ".poll")
+	}
+}
+
 func loadConfigFile() error {
+	rootFlags.cacheTTL = defaultCacheTTL
+	rootFlags.pollInterval = defaultPollInterval
+
 	path, err := expandPath(rootFlags.configFile)
 	if err != nil {
 		return err
@@ -54,9 +145,145 @@ func loadConfigFile() error {
 		setLogger(rootFlags.logLevel)
 	}
 
+	rootFlags.sourceCheckouts = k.StringMap("sources")
+	rootFlags.cacheDir = k.String("cache.dir")
+	if k.Exists("cache.ttl") {
+		rootFlags.cacheTTL = k.Duration("cache.ttl")
+	}
+	if k.Bool("cache.disabled") {
+		rootFlags.noCache = true
+	}
+	if k.Exists("mouse") && !k.Bool("mouse") {
+		rootFlags.noMouse = true
+	}
+	loadEvents("events")
+	if err := loadLayout(""); err != nil {
+		return err
+	}
+	if err := loadKeys("keys"); err != nil {
+		return err
+	}
+	if err := loadNotifications("notifications"); err != nil {
+		return err
+	}
+
+	return loadColumns("")
+}
+
+// loadKeys loads the keys overriding the default keys, by context and binding name
+// The keys of a binding are either a single key or a list
+func loadKeys(path string) error {
+	rootFlags.keys = make(map[string]map[string][]string)
+	for _, context := range k.MapKeys(path) {
+		rootFlags.keys[context] = make(map[string][]string)
+		for _, name := range k.MapKeys(path + "." + context) {
+			keys, err := stringList(path + "." + context + "." + name)
+			if err != nil {
+				return fmt.Errorf("keys: %w", err)
+			}
+			rootFlags.keys[context][name] = keys
+		}
+	}
 	return nil
 }
 
+// loadNotifications loads the notifications of the pipelines transitions, a profile can override the global settings
+// Rules select the pipelines by name like the filters, and their transitions, the methods and transitions are either a single value or a list
+func loadNotifications(path string) error {
+	if !k.Exists(path) {
+		return nil
+	}
+
+	cfg := &notify.Config{}
+	var err error
+	if cfg.Methods, err = stringList(path + ".methods"); err != nil {
+		return fmt.Errorf("notifications: %w", err)
+	}
+	if cfg.Command, err = stringList(path + ".command"); err != nil {
+		return fmt.Errorf("notifications: %w", err)
+	}
+	if k.Exists(path + ".rules") {
+		raw, ok := k.Get(path + ".rules").([]interface{})
+		if !ok {
+			return fmt.Errorf("notifications: rules must be a list")
+		}
+		for i, r := range raw {
+			fields, ok := r.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("notifications: invalid rule %v", r)
+			}
+			var rule notify.Rule
+			var include, exclude []string
+			name := fmt.Sprintf("%v.rules.%d", path, i)
+			if include, err = values(name+".pipelines", fields["pipelines"]); err != nil {
+				return err
+			}
+			if exclude, err = values(name+".exclude", fields["exclude"]); err != nil {
+				return err
+			}
+			if rule.Pipelines, err = awsqueries.NewNameFilter(include, exclude); err != nil {
+				return fmt.Errorf("%v: %w", name, err)
+			}
+			if rule.Transitions, err = values(name+".transitions", fields["transitions"]); err != nil {
+				return err
+			}
+			cfg.Rules = append(cfg.Rules, rule)
+		}
+	}
+	rootFlags.notifications = cfg
+	return nil
+}
+
+// loadLayout loads the layout of the views, a profile can override the global settings
+// The split layout displays the pipelines, the selected pipeline and the selected action side by side
+func loadLayout(prefix string) error {
+	if !k.Exists(prefix + "layout") {
+		return nil
+	}
+	switch layout := k.String(prefix + "layout"); layout {
+	case "split":
+		rootFlags.split = true
+	case "single":
+		rootFlags.split = false
+	default:
+		return fmt.Errorf("invalid layout %v, expected split or single", layout)
+	}
+	return nil
+}
+
+// loadColumns loads the columns and the sort of the pipelines table, a profile can override the global settings
+// Columns are either a name, e.g. status, or a map with the name, the width and the tag of the tag column
+func loadColumns(prefix string) error {
+	if k.Exists(prefix + "columns") {
+		raw, ok := k.Get(prefix + "columns").([]interface{})
+		if !ok {
+			return fmt.Errorf("columns must be a list")
+		}
+		columns := make([]tui.Column, len(raw))
+		for i, c := range raw {
+			switch c := c.(type) {
+			case string:
+				columns[i].Name = c
+			case map[string]interface{}:
+				columns[i].Name, _ = c["name"].(string)
+				columns[i].Tag, _ = c["tag"].(string)
+				if width, ok := c["width"]; ok {
+					if _, err := fmt.Sscan(fmt.Sprint(width), &columns[i].Width); err != nil {
+						return fmt.Errorf("invalid width of column %v: %v", columns[i].Name, width)
+					}
+				}
+			default:
+				return fmt.Errorf("invalid column %v", c)
+			}
+		}
+		rootFlags.columns = columns
+	}
+	if k.Exists(prefix + "sort") {
+		rootFlags.sort = k.String(prefix + "sort")
+	}
+	return tui.ValidateColumns(rootFlags.columns, rootFlags.sort)
+}
+
 // ExpandPath takes a directory path as input, resolves any environment variables, and returns the full path.
 // It replace ${VAR} and $VAR with the value of the environment variable
 func expandPath(path string) (string, error) {
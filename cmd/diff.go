@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+	"github.com/fabio42/codeplumber/pipeline"
+
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var diffFlags struct {
+	exitCode bool
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [--profile PROFILE_NAME] FROM TO...",
+	Short: "Compare CodePipeline definitions",
+	Long: `Compare the definition of a CodePipeline with one or more CodePipelines.
+Each definition is a CodePipeline name, a CodePipeline name and a version (NAME:VERSION) or a local JSON/YAML file,
+in the format of 'aws codepipeline get-pipeline' or a bare pipeline declaration.
+Stages, actions, artifacts and triggers are matched by name; the name and version of the CodePipelines are ignored.`,
+	Example: `  codeplumber diff golden-template.yaml myTeam-service-a myTeam-service-b
+  codeplumber diff myTeam-service-a:3 myTeam-service-a`,
+	Args: cobra.MinimumNArgs(2),
+
+	ValidArgsFunction: func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveDefault
+	},
+
+	Run: func(_ *cobra.Command, args []string) {
+		if rootFlags.profile != "" {
+			if err := loadProfile(rootFlags.profile); err != nil {
+				log.Fatal().Msgf("Error loading profile: %s", err)
+			}
+		}
+
+		log.Debug().Str("model", "cmd").Str("func", "diffCmdRun").Msgf("Definitions: %v", args)
+		log.Debug().Str("model", "cmd").Str("func", "diffCmdRun").Msgf("AWS profile: %s", rootFlags.awsProfile)
+		log.Debug().Str("model", "cmd").Str("func", "diffCmdRun").Msgf("AWS region: %s", rootFlags.awsRegion)
+
+		changed, err := diff(args[0], args[1:])
+		if err != nil {
+			log.Fatal().Msgf("Error comparing CodePipelines: %s", err)
+		}
+		if changed && diffFlags.exitCode {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&rootFlags.profile, "profile", "", "Use the AWS settings of a profile.")
+	diffCmd.Flags().BoolVar(&diffFlags.exitCode, "exit-code", false, "Exit with 1 when the definitions differ.")
+	diffCmd.RegisterFlagCompletionFunc("profile", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return getProfiles(), cobra.ShellCompDirectiveNoFileComp
+	})
+	rootCmd.AddCommand(diffCmd)
+}
+
+// diff prints the differences between the definition from and each definition of to, it returns true if any differ
+func diff(from string, to []string) (bool, error) {
+	var get func(string, int32) (*codepipeline.GetPipelineOutput, error)
+	load := func(s string) (pipeline.Source, pipeline.Document, error) {
+		source, err := pipeline.ParseSource(s)
+		if err != nil {
+			return source, nil, err
+		}
+		// AWS is only configured when a CodePipeline is compared
+		if source.File == "" && get == nil {
+			cfg, err := awsConfig()
+			if err != nil {
+				return source, nil, err
+			}
+			get = func(name string, version int32) (*codepipeline.GetPipelineOutput, error) {
+				return awsqueries.GetPipelineVersion(cfg, name, version)
+			}
+		}
+		doc, err := source.Document(get)
+		if err != nil {
+			return source, nil, fmt.Errorf("failed to load %v: %w", source, err)
+		}
+		return source, doc, nil
+	}
+
+	fromSource, fromDoc, err := load(from)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	for i, s := range to {
+		toSource, toDoc, err := load(s)
+		if err != nil {
+			return changed, err
+		}
+
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("--- %v\n+++ %v\n", fromSource, toSource)
+		changes := pipeline.Diff(fromDoc, toDoc)
+		if len(changes) == 0 {
+			fmt.Println("No differences")
+		}
+		for _, change := range changes {
+			fmt.Println(change)
+		}
+		changed = changed || len(changes) > 0
+	}
+	return changed, nil
+}
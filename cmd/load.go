@@ -29,13 +29,15 @@ var loadCmd = &cobra.Command{
 		}
 
 		log.Debug().Str("model", "cmd").Str("func", "loadCmdRun").Msgf("Loading profile %s", args[0])
-		log.Debug().Str("model", "cmd").Str("func", "loadCmdRun").Msgf("Name filter: %s", rootFlags.nameFilter)
+		log.Debug().Str("model", "cmd").Str("func", "loadCmdRun").Msgf("Name filter: %v, exclude: %v", rootFlags.nameFilter, rootFlags.nameExclude)
 		log.Debug().Str("model", "cmd").Str("func", "loadCmdRun").Msgf("Extra name filter: %s", rootFlags.nameFilterExtra)
 		log.Debug().Str("model", "cmd").Str("func", "loadCmdRun").Msgf("AWS profile: %s", rootFlags.awsProfile)
 		log.Debug().Str("model", "cmd").Str("func", "loadCmdRun").Msgf("AWS region: %s", rootFlags.awsRegion)
 		log.Debug().Str("model", "cmd").Str("func", "loadCmdRun").Msgf("Tags filter: %v", rootFlags.tagsFilter)
 
-		run()
+		if err := run(); err != nil {
+			log.Fatal().Msgf("Error: %v", err)
+		}
 	},
 }
 
@@ -3,6 +3,11 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+	"github.com/fabio42/codeplumber/notify"
+	"github.com/fabio42/codeplumber/tui"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -11,17 +16,37 @@ import (
 var rootFlags struct {
 	awsProfile      string
 	awsRegion       string
+	cacheDir        string
+	cacheTTL        time.Duration
+	columns         []tui.Column
+	concurrency     int
 	configFile      string
+	dashboard       bool
 	debug           bool
+	endpoint        string
+	endpoints       map[string]string
+	eventsHTTP      string
+	eventsSQS       string
+	keys            map[string]map[string][]string
 	logLevel        string
 	listProfiles    bool
-	nameFilter      string
+	nameFilter      []string
+	nameExclude     []string
 	nameFilterExtra string
+	noCache         bool
 	noConfig        bool
+	noMouse         bool
+	notifications   *notify.Config
+	pollInterval    time.Duration
 	profile         string
+	rateLimit       float64
 	record, replay  bool
 	recordDir       string
-	tagsFilter      map[string]string
+	sort            string
+	sourceCheckouts map[string]string
+	split           bool
+	tagsFilter      map[string][]string
+	tagsFlag        map[string]string
 }
 
 var rootCmd = &cobra.Command{
@@ -47,6 +72,11 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&rootFlags.configFile, "config", "c", "$HOME/.config/codeplumber/config.yaml", "CodePlumber Configuration file location.")
 	rootCmd.PersistentFlags().StringVarP(&rootFlags.awsProfile, "aws-profile", "p", "", "Use a specific AWS profile from your AWS credential file, overwrite ENV variable AWS_PROFILE.")
 	rootCmd.PersistentFlags().StringVarP(&rootFlags.awsRegion, "aws-region", "r", "us-east-1", "The AWS region to use, overwrite ENV variable AWS_REGION.")
+	rootCmd.PersistentFlags().IntVar(&rootFlags.concurrency, "concurrency", awsqueries.DefaultConcurrency, "Maximum number of CodePipelines described in parallel.")
+	rootCmd.PersistentFlags().Float64Var(&rootFlags.rateLimit, "rate-limit", 10, "Maximum number of AWS API requests per second, 0 to disable the limit.")
+	rootCmd.PersistentFlags().BoolVar(&rootFlags.noCache, "no-cache", false, "Do not use the on-disk cache of the CodePipelines listing.")
+	rootCmd.PersistentFlags().BoolVar(&rootFlags.dashboard, "dashboard", false, "Open the dashboard of the CodePipelines on startup.")
+	rootCmd.PersistentFlags().StringVar(&rootFlags.endpoint, "endpoint-url", "", "Override the endpoint of all the AWS services, e.g. http://localhost:4566 for LocalStack.")
 	rootCmd.PersistentFlags().BoolVarP(&rootFlags.debug, "debug", "d", false, "Enable debug log, out will be saved in "+logFile)
 
 	// TODO: Consider making this attribute mandatory when using record/replay options
@@ -3,7 +3,13 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
+	awsqueries "github.com/fabio42/codeplumber/aws"
+	"github.com/fabio42/codeplumber/events"
+	"github.com/fabio42/codeplumber/notify"
 	"github.com/fabio42/codeplumber/tui"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -21,41 +27,37 @@ var runCmd = &cobra.Command{
 	Run: func(_ *cobra.Command, args []string) {
 
 		if len(args) > 0 {
-			rootFlags.nameFilter = args[0]
+			rootFlags.nameFilter = []string{args[0]}
+		}
+		if rootFlags.tagsFilter == nil {
+			rootFlags.tagsFilter = make(map[string][]string)
+		}
+		for key, values := range rootFlags.tagsFlag {
+			rootFlags.tagsFilter[key] = strings.Split(values, "|")
 		}
 
-		log.Debug().Str("model", "cmd").Str("func", "runCmdRun").Msgf("Name filter: %s", rootFlags.nameFilter)
+		log.Debug().Str("model", "cmd").Str("func", "runCmdRun").Msgf("Name filter: %v, exclude: %v", rootFlags.nameFilter, rootFlags.nameExclude)
 		log.Debug().Str("model", "cmd").Str("func", "runCmdRun").Msgf("Extra name filter: %s", rootFlags.nameFilterExtra)
 		log.Debug().Str("model", "cmd").Str("func", "runCmdRun").Msgf("AWS profile: %s", rootFlags.awsProfile)
 		log.Debug().Str("model", "cmd").Str("func", "runCmdRun").Msgf("AWS region: %s", rootFlags.awsRegion)
 		log.Debug().Str("model", "cmd").Str("func", "runCmdRun").Msgf("Tags filter: %v", rootFlags.tagsFilter)
 
-		// Filtering only on tags is not supported natively by AWS APIs and quite ineffective as we need to describe
-		// all resources one by one to extract the tags. For this reason filtering only tags is not allowed.
-		if len(rootFlags.tagsFilter) > 0 && rootFlags.nameFilter == "" {
-			log.Fatal().Msg("Filtering only on tags is not supported at the moment, please provide a name filter as well.")
+		if err := run(); err != nil {
+			log.Fatal().Msgf("Error: %v", err)
 		}
-
-		run()
 	},
 }
 
 func init() {
-	runCmd.Flags().StringToStringVarP(&rootFlags.tagsFilter, "tags", "t", nil, "Filter resources by tags, e.g. --tags key1=value1,key2=value2")
+	runCmd.Flags().StringToStringVarP(&rootFlags.tagsFlag, "tags", "t", nil, "Filter resources by tags, e.g. --tags key1=value1,key2=value2|value3,key3=*")
 	rootCmd.AddCommand(runCmd)
 }
 
 func run() error {
 	log.Info().Msg("Starting codeplumber")
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithRegion(rootFlags.awsRegion),
-		config.WithSharedConfigProfile(rootFlags.awsProfile),
-		config.WithRetryer(func() aws.Retryer {
-			return retry.AddWithMaxAttempts(retry.NewStandard(), 5)
-		}),
-	)
+	cfg, err := awsConfig()
 	if err != nil {
-		return fmt.Errorf("failed to load AWS configuration: %w", err)
+		return err
 	}
 
 	var tuicfg tui.Config
@@ -72,11 +74,95 @@ func run() error {
 		return err
 	}
 	tuicfg.RecordDir = recordDir
-	tuicfg.NameFilter = rootFlags.nameFilter
+	if tuicfg.NameFilter, err = awsqueries.NewNameFilter(rootFlags.nameFilter, rootFlags.nameExclude); err != nil {
+		return fmt.Errorf("invalid name filter: %w", err)
+	}
 	tuicfg.NameFilterExtra = rootFlags.nameFilterExtra
-	tuicfg.TagFilter = rootFlags.tagsFilter
+	if tuicfg.TagFilter, err = awsqueries.NewTagFilter(rootFlags.tagsFilter); err != nil {
+		return fmt.Errorf("invalid tags filter: %w", err)
+	}
+	tuicfg.Concurrency = rootFlags.concurrency
+	tuicfg.Columns = rootFlags.columns
+	tuicfg.Sort = rootFlags.sort
+	tuicfg.Dashboard = rootFlags.dashboard
+	tuicfg.Split = rootFlags.split
+	if rootFlags.notifications != nil {
+		if tuicfg.Notifier, err = notify.New(*rootFlags.notifications, os.Stdout); err != nil {
+			return fmt.Errorf("invalid notifications: %w", err)
+		}
+	}
+	if err := tui.ConfigureKeys(rootFlags.keys); err != nil {
+		return fmt.Errorf("invalid keys: %w", err)
+	}
+	if tuicfg.StatePath, err = statePath(); err != nil {
+		return err
+	}
+	if !rootFlags.noCache {
+		if tuicfg.CacheDir, err = cacheDir(); err != nil {
+			return err
+		}
+		tuicfg.CacheTTL = rootFlags.cacheTTL
+	}
+	tuicfg.SourceCheckouts = make(map[string]string)
+	for repository, dir := range rootFlags.sourceCheckouts {
+		if tuicfg.SourceCheckouts[repository], err = expandPath(dir); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case rootFlags.eventsSQS != "":
+		tuicfg.EventSource = events.NewSQSSource(cfg, rootFlags.eventsSQS)
+	case rootFlags.eventsHTTP != "":
+		tuicfg.EventSource = events.NewHTTPSource(rootFlags.eventsHTTP)
+	default:
+		tuicfg.PollInterval = rootFlags.pollInterval
+	}
 
 	m := tui.NewModel(tuicfg)
-	_, err = tea.NewProgram(m).Run()
+	var opts []tea.ProgramOption
+	if !rootFlags.noMouse {
+		// The terminal selects the text with the shift key while the mouse is captured
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	_, err = tea.NewProgram(m, opts...).Run()
 	return err
 }
+
+// cacheDir returns the directory of the on-disk cache, $XDG_CACHE_HOME/codeplumber by default
+func cacheDir() (string, error) {
+	if rootFlags.cacheDir != "" {
+		return expandPath(rootFlags.cacheDir)
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the cache directory: %w", err)
+	}
+	return filepath.Join(dir, "codeplumber"), nil
+}
+
+// statePath returns the path of the state file of the sessions, next to the configuration file
+func statePath() (string, error) {
+	path, err := expandPath(rootFlags.configFile)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "state.json"), nil
+}
+
+// awsConfig returns the AWS configuration of the selected AWS profile, region and endpoints
+func awsConfig() (aws.Config, error) {
+	cfg, err := config.LoadDefaultConfig(context.TODO(),
+		config.WithRegion(rootFlags.awsRegion),
+		config.WithSharedConfigProfile(rootFlags.awsProfile),
+		config.WithRetryer(func() aws.Retryer {
+			return retry.AddWithMaxAttempts(retry.NewStandard(), 5)
+		}),
+	)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	awsqueries.SetEndpoints(&cfg, rootFlags.endpoint, rootFlags.endpoints)
+	awsqueries.Throttle(&cfg, rootFlags.rateLimit)
+	return cfg, nil
+}
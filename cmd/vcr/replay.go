@@ -5,6 +5,7 @@ import (
 	"time"
 
 	awsqueries "github.com/fabio42/codeplumber/aws"
+	"github.com/fabio42/codeplumber/models/table"
 
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 )
@@ -28,3 +29,15 @@ func (r *Recorder) GetCloudWatchLogs(record string) (*cloudwatchlogs.GetLogEvent
 	err := Load(fmt.Sprintf("%s/%s", r.RecordDir, record), &cwlData)
 	return cwlData, err
 }
+
+func (r *Recorder) GetBuildspec(record string) (string, error) {
+	var buildspec string
+	err := Load(fmt.Sprintf("%s/%s", r.RecordDir, record), &buildspec)
+	return buildspec, err
+}
+
+func (r *Recorder) GetTableRows(record string) ([]table.Row, error) {
+	rows := []table.Row{}
+	err := Load(fmt.Sprintf("%s/%s", r.RecordDir, record), &rows)
+	return rows, err
+}
@@ -0,0 +1,137 @@
+// Package events receives the AWS CodePipeline and AWS CodeBuild state change events published by EventBridge
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventBridge detail types of the supported events
+const (
+	PipelineExecutionChange = "CodePipeline Pipeline Execution State Change"
+	StageExecutionChange    = "CodePipeline Stage Execution State Change"
+	ActionExecutionChange   = "CodePipeline Action Execution State Change"
+	BuildStateChange        = "CodeBuild Build State Change"
+	BuildPhaseChange        = "CodeBuild Build Phase Change"
+)
+
+// Source is a source of events, Run sends the events to ch until ctx is canceled or an error occurs
+type Source interface {
+	Run(ctx context.Context, ch chan<- Event) error
+}
+
+// Event is a CodePipeline or CodeBuild state change
+type Event struct {
+	DetailType  string
+	Account     string
+	Region      string
+	Time        time.Time
+	Pipeline    string
+	ExecutionID string
+	Stage       string
+	Action      string
+	// State is the EventBridge state of the pipeline, stage or action: STARTED, SUCCEEDED, FAILED...
+	State   string
+	Project string
+	BuildID string
+	// BuildStatus is the status of a build: IN_PROGRESS, SUCCEEDED, FAILED...
+	BuildStatus string
+	// Category is the category of an action: Source, Build, Approval...
+	Category string
+}
+
+type envelope struct {
+	DetailType string    `json:"detail-type"`
+	Account    string    `json:"account"`
+	Region     string    `json:"region"`
+	Time       time.Time `json:"time"`
+	Detail     struct {
+		Pipeline    string `json:"pipeline"`
+		ExecutionID string `json:"execution-id"`
+		Stage       string `json:"stage"`
+		Action      string `json:"action"`
+		State       string `json:"state"`
+		ProjectName string `json:"project-name"`
+		BuildID     string `json:"build-id"`
+		BuildStatus string `json:"build-status"`
+		Type        struct {
+			Category string `json:"category"`
+		} `json:"type"`
+	} `json:"detail"`
+}
+
+// snsNotification is the envelope of the events delivered through an SNS topic
+type snsNotification struct {
+	Type    string `json:"Type"`
+	Message string `json:"Message"`
+}
+
+// Parse returns the event of an EventBridge message, events delivered through SNS are unwrapped
+func Parse(data []byte) (Event, error) {
+	var notification snsNotification
+	if err := json.Unmarshal(data, &notification); err == nil && notification.Type == "Notification" {
+		data = []byte(notification.Message)
+	}
+
+	var e envelope
+	if err := json.Unmarshal(data, &e); err != nil {
+		return Event{}, err
+	}
+
+	switch e.DetailType {
+	case PipelineExecutionChange, StageExecutionChange, ActionExecutionChange, BuildStateChange, BuildPhaseChange:
+	default:
+		return Event{}, fmt.Errorf("unsupported event: %q", e.DetailType)
+	}
+
+	return Event{
+		DetailType:  e.DetailType,
+		Account:     e.Account,
+		Region:      e.Region,
+		Time:        e.Time,
+		Pipeline:    e.Detail.Pipeline,
+		ExecutionID: e.Detail.ExecutionID,
+		Stage:       e.Detail.Stage,
+		Action:      e.Detail.Action,
+		State:       e.Detail.State,
+		Project:     e.Detail.ProjectName,
+		BuildID:     e.Detail.BuildID,
+		BuildStatus: e.Detail.BuildStatus,
+		Category:    e.Detail.Type.Category,
+	}, nil
+}
+
+// IsPipeline returns true for CodePipeline events
+func (e Event) IsPipeline() bool {
+	return strings.HasPrefix(e.DetailType, "CodePipeline")
+}
+
+// BuildExecutionID returns the build ID as referenced by CodePipeline actions, project:uuid
+func (e Event) BuildExecutionID() string {
+	return e.BuildID[strings.LastIndex(e.BuildID, "/")+1:]
+}
+
+// PipelineStatus returns the CodePipeline API status matching an EventBridge state
+func PipelineStatus(state string) string {
+	switch state {
+	case "STARTED", "RESUMED":
+		return "InProgress"
+	case "SUCCEEDED":
+		return "Succeeded"
+	case "FAILED":
+		return "Failed"
+	case "CANCELED":
+		return "Cancelled"
+	case "SUPERSEDED":
+		return "Superseded"
+	case "STOPPED":
+		return "Stopped"
+	case "STOPPING":
+		return "Stopping"
+	default:
+		return state
+	}
+}
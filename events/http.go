@@ -0,0 +1,69 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// HTTPSource receives events posted to a local HTTP endpoint, by an EventBridge API destination
+// behind a tunnel or by a local script replaying events
+type HTTPSource struct {
+	Addr string
+}
+
+// NewHTTPSource returns a new HTTPSource listening on addr
+func NewHTTPSource(addr string) *HTTPSource {
+	return &HTTPSource{Addr: addr}
+}
+
+// Run implement the Source interface
+// The body of a POST request is an event or a JSON array of events
+func (s *HTTPSource) Run(ctx context.Context, ch chan<- Event) error {
+	server := &http.Server{
+		Addr:              s.Addr,
+		ReadHeaderTimeout: 10 * time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			var batch []json.RawMessage
+			if err := json.Unmarshal(body, &batch); err != nil {
+				batch = []json.RawMessage{body}
+			}
+
+			for _, data := range batch {
+				e, err := Parse(data)
+				if err != nil {
+					log.Debug().Str("model", "events").Str("func", "HTTPSource.Run").Msgf("ignoring event: %v", err)
+					continue
+				}
+				ch <- e
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	}
+
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rs/zerolog/log"
+)
+
+// SQSSource receives the events delivered by an EventBridge rule to an SQS queue
+type SQSSource struct {
+	Config   aws.Config
+	QueueURL string
+}
+
+// NewSQSSource returns a new SQSSource
+func NewSQSSource(cfg aws.Config, queueURL string) *SQSSource {
+	return &SQSSource{Config: cfg, QueueURL: queueURL}
+}
+
+// Run implement the Source interface, messages are deleted once parsed
+func (s *SQSSource) Run(ctx context.Context, ch chan<- Event) error {
+	for ctx.Err() == nil {
+		messages, err := awsqueries.ReceiveSQSMessages(ctx, s.Config, s.QueueURL, 20)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return err
+		}
+
+		for _, message := range messages {
+			e, err := Parse([]byte(aws.ToString(message.Body)))
+			if err != nil {
+				log.Debug().Str("model", "events").Str("func", "SQSSource.Run").Msgf("ignoring message %v: %v", aws.ToString(message.MessageId), err)
+			} else {
+				ch <- e
+			}
+
+			if err := awsqueries.DeleteSQSMessage(ctx, s.Config, s.QueueURL, aws.ToString(message.ReceiptHandle)); err != nil {
+				log.Debug().Str("model", "events").Str("func", "SQSSource.Run").Msgf("failed to delete message %v: %v", aws.ToString(message.MessageId), err)
+			}
+		}
+	}
+	return nil
+}
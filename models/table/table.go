@@ -8,6 +8,7 @@ package table
 
 import (
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -29,8 +30,18 @@ type Model struct {
 	viewport viewport.Model
 	start    int
 	end      int
+
+	// The last click on a row, a second click on the same row within doubleClickInterval selects it.
+	clickRow  int
+	clickTime time.Time
 }
 
+// doubleClickInterval is the maximum time between the two clicks of a double click
+const doubleClickInterval = 400 * time.Millisecond
+
+// SelectMsg is sent when a row is double clicked, the row is the selected row.
+type SelectMsg struct{}
+
 // CellPosition holds row and column indexes.
 type CellPosition struct {
 	RowID         int
@@ -249,11 +260,59 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		case key.Matches(msg, m.KeyMap.GotoBottom):
 			m.GotoBottom()
 		}
+
+	case tea.MouseMsg:
+		// The coordinates of the mouse are relative to the top left corner of the table.
+		if msg.Action != tea.MouseActionPress {
+			break
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.MoveUp(1)
+		case tea.MouseButtonWheelDown:
+			m.MoveDown(1)
+		case tea.MouseButtonLeft:
+			return m, m.click(msg)
+		}
 	}
 
 	return m, nil
 }
 
+// click moves the cursor to the clicked row, the rows displayed are kept in place.
+// It returns a command sending a SelectMsg when the row is double clicked.
+func (m *Model) click(msg tea.MouseMsg) tea.Cmd {
+	row, ok := m.RowAt(msg.Y)
+	if !ok {
+		return nil
+	}
+
+	top := m.start + m.viewport.YOffset
+	m.SetCursor(row)
+	m.viewport.SetYOffset(top - m.start)
+
+	if row == m.clickRow && time.Since(m.clickTime) < doubleClickInterval {
+		m.clickTime = time.Time{}
+		return func() tea.Msg { return SelectMsg{} }
+	}
+	m.clickRow, m.clickTime = row, time.Now()
+	return nil
+}
+
+// RowAt returns the index of the row displayed at the line y of the table, the
+// first line being the headers. It returns false when no row is displayed at y.
+func (m Model) RowAt(y int) (int, bool) {
+	line := y - lipgloss.Height(m.headersView())
+	if line < 0 || line >= m.viewport.Height {
+		return 0, false
+	}
+	row := m.start + m.viewport.YOffset + line
+	if row >= m.end {
+		return 0, false
+	}
+	return row, true
+}
+
 // Focused returns the focus state of the table.
 func (m Model) Focused() bool {
 	return m.focus
@@ -0,0 +1,187 @@
+// Package notify emits the notifications of the AWS CodePipelines state changes, in the terminal and on the desktop
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Transitions of the CodePipelines which notify
+const (
+	Started   = "started"
+	Succeeded = "succeeded"
+	Failed    = "failed"
+	Approval  = "approval"
+)
+
+// Methods of the notifications
+const (
+	// Bell rings the terminal bell
+	Bell = "bell"
+	// OSC9 is the notification escape sequence of iTerm2, Windows Terminal, kitty, WezTerm...
+	OSC9 = "osc9"
+	// OSC777 is the notification escape sequence of rxvt-unicode, foot, Ghostty and the VTE terminals
+	OSC777 = "osc777"
+	// Desktop runs the desktop notification command
+	Desktop = "desktop"
+)
+
+var (
+	// Transitions are the supported transitions
+	Transitions = []string{Started, Succeeded, Failed, Approval}
+	// Methods are the supported methods
+	Methods = []string{Bell, OSC9, OSC777, Desktop}
+	// DefaultMethods are the methods used when none is configured
+	DefaultMethods = []string{Bell}
+	// DefaultCommand is the desktop notification command, {title} and {message} are replaced in its arguments
+	DefaultCommand = []string{"notify-send", "--app-name=codeplumber", "{title}", "{message}"}
+)
+
+// Notification is a transition of a CodePipeline
+type Notification struct {
+	Pipeline   string
+	Transition string
+	// Stage and Action are the approval action waiting for a review
+	Stage  string
+	Action string
+}
+
+// Title returns the title of the notification
+func (n Notification) Title() string {
+	return "codeplumber: " + n.Pipeline
+}
+
+// Message returns the description of the transition
+func (n Notification) Message() string {
+	switch n.Transition {
+	case Approval:
+		return fmt.Sprintf("%v is waiting for approval at %v/%v", n.Pipeline, n.Stage, n.Action)
+	default:
+		return fmt.Sprintf("%v %v", n.Pipeline, n.Transition)
+	}
+}
+
+// Rule selects the CodePipelines and their transitions which notify, all the transitions when none is set
+type Rule struct {
+	Pipelines   awsqueries.NameFilter
+	Transitions []string
+}
+
+func (r Rule) match(pipeline, transition string) bool {
+	return r.Pipelines.Match(pipeline) && (len(r.Transitions) == 0 || slices.Contains(r.Transitions, transition))
+}
+
+// Config are the settings of the notifications, all the transitions of all the CodePipelines notify when no rule is set
+type Config struct {
+	Methods []string
+	Command []string
+	Rules   []Rule
+}
+
+// Notifier emits the notifications matching its rules
+// It is used from the Update loop of the program, the escape sequences are written between two frames
+type Notifier struct {
+	config Config
+	out    io.Writer
+}
+
+// New returns a notifier writing the escape sequences of the terminal notifications to out
+func New(cfg Config, out io.Writer) (*Notifier, error) {
+	if len(cfg.Methods) == 0 {
+		cfg.Methods = DefaultMethods
+	}
+	for _, method := range cfg.Methods {
+		if !slices.Contains(Methods, method) {
+			return nil, fmt.Errorf("unknown notification method %v, supported methods: %v", method, strings.Join(Methods, ", "))
+		}
+	}
+	if len(cfg.Command) == 0 {
+		cfg.Command = DefaultCommand
+	}
+	for _, rule := range cfg.Rules {
+		for _, transition := range rule.Transitions {
+			if !slices.Contains(Transitions, transition) {
+				return nil, fmt.Errorf("unknown transition %v, supported transitions: %v", transition, strings.Join(Transitions, ", "))
+			}
+		}
+	}
+	if len(cfg.Rules) == 0 {
+		cfg.Rules = []Rule{{}}
+	}
+	return &Notifier{config: cfg, out: out}, nil
+}
+
+// Watches returns true when a transition of a CodePipeline notifies
+func (n *Notifier) Watches(pipeline, transition string) bool {
+	for _, rule := range n.config.Rules {
+		if rule.match(pipeline, transition) {
+			return true
+		}
+	}
+	return false
+}
+
+// Notify emits a notification with the configured methods when it matches a rule
+func (n *Notifier) Notify(notification Notification) {
+	if !n.Watches(notification.Pipeline, notification.Transition) {
+		return
+	}
+	log.Debug().Str("model", "notify").Str("func", "Notify").Msgf("%v: %v", notification.Title(), notification.Message())
+
+	// The sequences are written at once, the renderer writes its frames in between
+	var sequences strings.Builder
+	title, message := sanitize(notification.Title()), sanitize(notification.Message())
+	for _, method := range n.config.Methods {
+		switch method {
+		case Bell:
+			sequences.WriteString("\a")
+		case OSC9:
+			fmt.Fprintf(&sequences, "\x1b]9;%v\x07", message)
+		case OSC777:
+			// The fields of the sequence are separated by semicolons
+			fmt.Fprintf(&sequences, "\x1b]777;notify;%v;%v\x07", strings.ReplaceAll(title, ";", ","), message)
+		case Desktop:
+			n.desktop(notification)
+		}
+	}
+	if sequences.Len() > 0 {
+		io.WriteString(n.out, sequences.String())
+	}
+}
+
+// desktop runs the desktop notification command in the background
+func (n *Notifier) desktop(notification Notification) {
+	replacer := strings.NewReplacer("{title}", notification.Title(), "{message}", notification.Message())
+	args := make([]string, len(n.config.Command))
+	for i, arg := range n.config.Command {
+		args[i] = replacer.Replace(arg)
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	if err := cmd.Start(); err != nil {
+		log.Debug().Str("model", "notify").Str("func", "desktop").Msgf("failed to run %v: %v", args[0], err)
+		return
+	}
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			log.Debug().Str("model", "notify").Str("func", "desktop").Msgf("%v failed: %v", args[0], err)
+		}
+	}()
+}
+
+// sanitize removes the control characters which would end the escape sequences
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s)
+}
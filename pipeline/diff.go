@@ -0,0 +1,288 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline"
+)
+
+// Kinds of Change
+const (
+	Added    = "+"
+	Removed  = "-"
+	Modified = "~"
+)
+
+// ignoredKeys always differ between two pipelines or two versions, they are not compared
+var ignoredKeys = []string{"name", "version"}
+
+// Source is a pipeline declaration to compare: NAME, NAME:VERSION or a local JSON/YAML file
+type Source struct {
+	Name    string
+	Version int32
+	File    string
+}
+
+// ParseSource returns the Source of s, existing files and paths with a JSON or YAML extension are local files
+func ParseSource(s string) (Source, error) {
+	switch strings.ToLower(filepath.Ext(s)) {
+	case ".json", ".yaml", ".yml":
+		return Source{File: s}, nil
+	}
+	if _, err := os.Stat(s); err == nil {
+		return Source{File: s}, nil
+	}
+
+	// ':' is not allowed in pipeline names
+	name, version, found := strings.Cut(s, ":")
+	if name == "" {
+		return Source{}, fmt.Errorf("invalid pipeline %q", s)
+	}
+	if !found {
+		return Source{Name: name}, nil
+	}
+	v, err := strconv.ParseInt(version, 10, 32)
+	if err != nil || v < 1 {
+		return Source{}, fmt.Errorf("invalid version of pipeline %q: %v", name, version)
+	}
+	return Source{Name: name, Version: int32(v)}, nil
+}
+
+func (s Source) String() string {
+	switch {
+	case s.File != "":
+		return s.File
+	case s.Version > 0:
+		return fmt.Sprintf("%v (version %v)", s.Name, s.Version)
+	default:
+		return s.Name
+	}
+}
+
+// Document returns the Document of the Source, pipelines are fetched with get
+func (s Source) Document(get func(name string, version int32) (*codepipeline.GetPipelineOutput, error)) (Document, error) {
+	if s.File != "" {
+		return Load(s.File)
+	}
+	out, err := get(s.Name, s.Version)
+	if err != nil {
+		return nil, err
+	}
+	return FromOutput(out)
+}
+
+// Change is a difference between two pipeline declarations
+// Path locates the change, named elements are referenced by name: stages[Build].actions[Compile].configuration.ProjectName
+type Change struct {
+	Kind string
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("%v %v%v", c.Kind, c.Path, formatValue(c.New, ": "))
+	case Removed:
+		return fmt.Sprintf("%v %v%v", c.Kind, c.Path, formatValue(c.Old, ": "))
+	default:
+		return fmt.Sprintf("%v %v: %v => %v", c.Kind, c.Path, formatValue(c.Old, ""), formatValue(c.New, ""))
+	}
+}
+
+// formatValue returns scalars and lists of scalars in JSON prefixed by prefix
+// Added and removed nested structures are only referenced by their path, their content is elided otherwise
+func formatValue(v interface{}, prefix string) string {
+	elided := ""
+	switch v := v.(type) {
+	case map[string]interface{}:
+		elided = "{…}"
+	case []interface{}:
+		if isStructured(v) {
+			elided = "[…]"
+		}
+	}
+	switch {
+	case elided != "" && prefix != "":
+		return ""
+	case elided != "":
+		return elided
+	}
+	b, _ := json.Marshal(v)
+	return prefix + string(b)
+}
+
+// Diff returns the structural differences of the declarations of two Documents
+// Stages, actions, artifacts, variables and triggers are matched by name, the metadata, name and version are ignored
+func Diff(from, to Document) []Change {
+	return diffValue("", strip(from.Declaration()), strip(to.Declaration()))
+}
+
+func strip(declaration map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(declaration))
+	for k, v := range declaration {
+		out[k] = v
+	}
+	for _, k := range ignoredKeys {
+		delete(out, k)
+	}
+	return out
+}
+
+func diffValue(path string, from, to interface{}) []Change {
+	if reflect.DeepEqual(from, to) {
+		return nil
+	}
+
+	switch from := from.(type) {
+	case map[string]interface{}:
+		if to, ok := to.(map[string]interface{}); ok {
+			return diffMap(path, from, to)
+		}
+	case []interface{}:
+		if to, ok := to.([]interface{}); ok {
+			return diffList(path, from, to)
+		}
+	}
+	return []Change{{Kind: Modified, Path: path, Old: from, New: to}}
+}
+
+func diffMap(path string, from, to map[string]interface{}) []Change {
+	keys := make([]string, 0, len(from)+len(to))
+	for k := range from {
+		keys = append(keys, k)
+	}
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []Change
+	for _, k := range keys {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		old, inFrom := from[k]
+		new, inTo := to[k]
+		switch {
+		case !inTo:
+			changes = append(changes, Change{Kind: Removed, Path: p, Old: old})
+		case !inFrom:
+			changes = append(changes, Change{Kind: Added, Path: p, New: new})
+		default:
+			changes = append(changes, diffValue(p, old, new)...)
+		}
+	}
+	return changes
+}
+
+// diffList matches named elements by name, other lists are compared element by element
+func diffList(path string, from, to []interface{}) []Change {
+	fromKeys, fromNamed := elementKeys(from)
+	toKeys, toNamed := elementKeys(to)
+	if !fromNamed || !toNamed {
+		if !isStructured(from) || !isStructured(to) {
+			return []Change{{Kind: Modified, Path: path, Old: from, New: to}}
+		}
+		var changes []Change
+		for i := 0; i < max(len(from), len(to)); i++ {
+			p := fmt.Sprintf("%v[%v]", path, i)
+			switch {
+			case i >= len(to):
+				changes = append(changes, Change{Kind: Removed, Path: p, Old: from[i]})
+			case i >= len(from):
+				changes = append(changes, Change{Kind: Added, Path: p, New: to[i]})
+			default:
+				changes = append(changes, diffValue(p, from[i], to[i])...)
+			}
+		}
+		return changes
+	}
+
+	toIdx := make(map[string]int, len(toKeys))
+	for i, k := range toKeys {
+		toIdx[k] = i
+	}
+	fromIdx := make(map[string]int, len(fromKeys))
+	for i, k := range fromKeys {
+		fromIdx[k] = i
+	}
+
+	var changes, common []Change
+	var fromOrder, toOrder []interface{}
+	for i, k := range fromKeys {
+		p := fmt.Sprintf("%v[%v]", path, k)
+		j, ok := toIdx[k]
+		if !ok {
+			changes = append(changes, Change{Kind: Removed, Path: p, Old: from[i]})
+			continue
+		}
+		fromOrder = append(fromOrder, k)
+		common = append(common, diffValue(p, from[i], to[j])...)
+	}
+	for j, k := range toKeys {
+		if _, ok := fromIdx[k]; !ok {
+			changes = append(changes, Change{Kind: Added, Path: fmt.Sprintf("%v[%v]", path, k), New: to[j]})
+		} else {
+			toOrder = append(toOrder, k)
+		}
+	}
+
+	// Stages and actions are also reordered
+	if !reflect.DeepEqual(fromOrder, toOrder) {
+		changes = append(changes, Change{Kind: Modified, Path: path + " order", Old: fromOrder, New: toOrder})
+	}
+	return append(changes, common...)
+}
+
+// elementKeys returns the names identifying the elements of a list, false when an element has no unique name
+func elementKeys(list []interface{}) ([]string, bool) {
+	keys := make([]string, len(list))
+	seen := make(map[string]bool, len(list))
+	for i, e := range list {
+		k, ok := elementKey(e)
+		if !ok || seen[k] {
+			return nil, false
+		}
+		seen[k] = true
+		keys[i] = k
+	}
+	return keys, true
+}
+
+func elementKey(e interface{}) (string, bool) {
+	m, ok := e.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	if name, ok := m["name"].(string); ok {
+		return name, true
+	}
+	// Triggers are identified by their source action
+	if git, ok := m["gitConfiguration"].(map[string]interface{}); ok {
+		if name, ok := git["sourceActionName"].(string); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func isStructured(list []interface{}) bool {
+	for _, e := range list {
+		if _, ok := e.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return len(list) > 0
+}
@@ -0,0 +1,202 @@
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseSource(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "declaration")
+	if err := os.WriteFile(file, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		source string
+		want   Source
+		err    bool
+	}{
+		{"my-pipeline", Source{Name: "my-pipeline"}, false},
+		{"my-pipeline:3", Source{Name: "my-pipeline", Version: 3}, false},
+		{"pipeline.json", Source{File: "pipeline.json"}, false},
+		{"dir/pipeline.YAML", Source{File: "dir/pipeline.YAML"}, false},
+		{"pipeline.yml", Source{File: "pipeline.yml"}, false},
+		{file, Source{File: file}, false},
+		{"my-pipeline:0", Source{}, true},
+		{"my-pipeline:-1", Source{}, true},
+		{"my-pipeline:latest", Source{}, true},
+		{"my-pipeline:", Source{}, true},
+		{":3", Source{}, true},
+		{"", Source{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			got, err := ParseSource(tt.source)
+			if (err != nil) != tt.err {
+				t.Fatalf("ParseSource(%q) error = %v, want error %v", tt.source, err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSource(%q) = %+v, want %+v", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+// testDocument returns a Document of a pipeline declaration with the given stages
+func testDocument(name string, version int, stages ...interface{}) Document {
+	return Document{
+		"pipeline": map[string]interface{}{
+			"name":    name,
+			"version": version,
+			"roleArn": "arn:aws:iam::123456789012:role/pipeline",
+			"stages":  stages,
+		},
+		"metadata": map[string]interface{}{"pipelineArn": "arn:aws:codepipeline:us-east-1:123456789012:" + name},
+	}
+}
+
+func testStage(name string, actions ...interface{}) map[string]interface{} {
+	return map[string]interface{}{"name": name, "actions": actions}
+}
+
+func testAction(name, project string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":          name,
+		"configuration": map[string]interface{}{"ProjectName": project},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	source := testStage("Source", testAction("Checkout", ""))
+	build := testStage("Build", testAction("Compile", "compile"), testAction("Test", "test"))
+	deploy := testStage("Deploy", testAction("Release", "release"))
+
+	tests := []struct {
+		name     string
+		from, to Document
+		want     []Change
+	}{
+		{
+			name: "name, version and metadata are ignored",
+			from: testDocument("a", 1, source, build),
+			to:   testDocument("b", 7, source, build),
+		},
+		{
+			name: "modified value",
+			from: testDocument("a", 1, source, build),
+			to:   testDocument("a", 2, source, testStage("Build", testAction("Compile", "compile-v2"), testAction("Test", "test"))),
+			want: []Change{
+				{Kind: Modified, Path: "stages[Build].actions[Compile].configuration.ProjectName", Old: "compile", New: "compile-v2"},
+			},
+		},
+		{
+			name: "added and removed stages",
+			from: testDocument("a", 1, source, build),
+			to:   testDocument("a", 2, source, deploy),
+			want: []Change{
+				{Kind: Removed, Path: "stages[Build]", Old: build},
+				{Kind: Added, Path: "stages[Deploy]", New: deploy},
+			},
+		},
+		{
+			name: "reordered actions",
+			from: testDocument("a", 1, source, build),
+			to:   testDocument("a", 2, source, testStage("Build", testAction("Test", "test"), testAction("Compile", "compile"))),
+			want: []Change{
+				{Kind: Modified, Path: "stages[Build].actions order", Old: []interface{}{"Compile", "Test"}, New: []interface{}{"Test", "Compile"}},
+			},
+		},
+		{
+			name: "reordered and modified",
+			from: testDocument("a", 1, source, build, deploy),
+			to:   testDocument("a", 2, source, deploy, testStage("Build", testAction("Compile", "compile"))),
+			want: []Change{
+				{Kind: Modified, Path: "stages order", Old: []interface{}{"Source", "Build", "Deploy"}, New: []interface{}{"Source", "Deploy", "Build"}},
+				{Kind: Removed, Path: "stages[Build].actions[Test]", Old: testAction("Test", "test")},
+			},
+		},
+		{
+			name: "added field",
+			from: testDocument("a", 1, source),
+			to: func() Document {
+				d := testDocument("a", 1, source)
+				d.Declaration()["pipelineType"] = "V2"
+				return d
+			}(),
+			want: []Change{
+				{Kind: Added, Path: "pipelineType", New: "V2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.from, tt.to)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLists(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to []interface{}
+		want     []Change
+	}{
+		{
+			name: "scalars are compared as a whole",
+			from: []interface{}{"a", "b"},
+			to:   []interface{}{"a", "c"},
+			want: []Change{{Kind: Modified, Path: "list", Old: []interface{}{"a", "b"}, New: []interface{}{"a", "c"}}},
+		},
+		{
+			name: "unnamed elements are compared by index",
+			from: []interface{}{map[string]interface{}{"key": "a"}},
+			to:   []interface{}{map[string]interface{}{"key": "b"}, map[string]interface{}{"key": "c"}},
+			want: []Change{
+				{Kind: Modified, Path: "list[0].key", Old: "a", New: "b"},
+				{Kind: Added, Path: "list[1]", New: map[string]interface{}{"key": "c"}},
+			},
+		},
+		{
+			name: "duplicate names are compared by index",
+			from: []interface{}{map[string]interface{}{"name": "a", "v": 1}, map[string]interface{}{"name": "a", "v": 2}},
+			to:   []interface{}{map[string]interface{}{"name": "a", "v": 1}, map[string]interface{}{"name": "a", "v": 3}},
+			want: []Change{{Kind: Modified, Path: "list[1].v", Old: 2, New: 3}},
+		},
+		{
+			name: "triggers are matched by source action",
+			from: []interface{}{map[string]interface{}{"gitConfiguration": map[string]interface{}{"sourceActionName": "Checkout", "branch": "main"}}},
+			to:   []interface{}{map[string]interface{}{"gitConfiguration": map[string]interface{}{"sourceActionName": "Checkout", "branch": "dev"}}},
+			want: []Change{{Kind: Modified, Path: "list[Checkout].gitConfiguration.branch", Old: "main", New: "dev"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffValue("list", tt.from, tt.to)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangeString(t *testing.T) {
+	tests := []struct {
+		change Change
+		want   string
+	}{
+		{Change{Kind: Added, Path: "pipelineType", New: "V2"}, `+ pipelineType: "V2"`},
+		{Change{Kind: Removed, Path: "stages[Build]", Old: map[string]interface{}{"name": "Build"}}, "- stages[Build]"},
+		{Change{Kind: Modified, Path: "stages order", Old: []interface{}{"A", "B"}, New: []interface{}{"B", "A"}}, `~ stages order: ["A","B"] => ["B","A"]`},
+		{Change{Kind: Modified, Path: "x", Old: map[string]interface{}{}, New: "v"}, `~ x: {…} => "v"`},
+	}
+	for _, tt := range tests {
+		if got := tt.change.String(); got != tt.want {
+			t.Errorf("%#v.String() = %q, want %q", tt.change, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+// Package pipeline handles AWS CodePipeline declarations in the format used by the AWS CLI
+package pipeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline"
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a pipeline as returned by `aws codepipeline get-pipeline`: {"pipeline": {...}, "metadata": {...}}
+type Document map[string]interface{}
+
+// userKeys are the fields holding user defined keys, these keys are kept as is
+var userKeys = map[string]bool{
+	"configuration":  true,
+	"artifactStores": true,
+}
+
+// FromOutput returns the Document of a GetPipeline response
+func FromOutput(out *codepipeline.GetPipelineOutput) (Document, error) {
+	if out == nil || out.Pipeline == nil {
+		return nil, fmt.Errorf("no pipeline declaration")
+	}
+
+	doc, err := FromDeclaration(out.Pipeline)
+	if err != nil {
+		return nil, err
+	}
+	if out.Metadata != nil {
+		metadata, err := convert(out.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		doc["metadata"] = metadata
+	}
+	return doc, nil
+}
+
+// FromDeclaration returns the Document of a pipeline declaration
+func FromDeclaration(declaration *types.PipelineDeclaration) (Document, error) {
+	pipeline, err := convert(declaration)
+	if err != nil {
+		return nil, err
+	}
+	return Document{"pipeline": pipeline}, nil
+}
+
+// Load reads a Document from a JSON or YAML file
+// Both the output of get-pipeline and a bare pipeline declaration are supported
+func Load(path string) (Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	default:
+		err = json.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %v: %w", path, err)
+	}
+
+	if _, ok := doc["pipeline"]; !ok {
+		doc = map[string]interface{}{"pipeline": doc}
+	}
+
+	// Numbers are decoded as the declarations fetched from AWS
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %v: %w", path, err)
+	}
+	doc = nil
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %v: %w", path, err)
+	}
+	return Document(stringConfiguration(doc).(map[string]interface{})), nil
+}
+
+// stringConfiguration converts the configuration values to strings as returned by AWS, YAML files often use booleans and numbers
+func stringConfiguration(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, value := range v {
+			configuration, ok := value.(map[string]interface{})
+			if k != "configuration" || !ok {
+				v[k] = stringConfiguration(value)
+				continue
+			}
+			for name, value := range configuration {
+				if _, ok := value.(string); !ok && value != nil {
+					configuration[name] = fmt.Sprint(value)
+				}
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = stringConfiguration(value)
+		}
+	}
+	return v
+}
+
+// Declaration returns the pipeline declaration of the Document
+func (d Document) Declaration() map[string]interface{} {
+	declaration, _ := d["pipeline"].(map[string]interface{})
+	return declaration
+}
+
+// JSON returns the Document formatted as the AWS CLI JSON output
+func (d Document) JSON() (string, error) {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(map[string]interface{}(d)); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// YAML returns the Document formatted as the AWS CLI YAML output
+func (d Document) YAML() (string, error) {
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(map[string]interface{}(d)); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// convert returns the SDK value as a generic value with the field names of the AWS CLI
+func convert(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	return camelCase(raw, false), nil
+}
+
+// camelCase converts the SDK field names to the field names of the AWS CLI: ActionTypeId => actionTypeId
+// Empty values are removed as the AWS CLI does not output them
+func camelCase(v interface{}, keepKeys bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			if keepKeys {
+				out[k] = camelCase(value, false)
+				continue
+			}
+			if isEmpty(value) {
+				continue
+			}
+			name := lowerFirst(k)
+			out[name] = camelCase(value, userKeys[name])
+		}
+		return out
+
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, value := range v {
+			out[i] = camelCase(value, false)
+		}
+		return out
+
+	default:
+		return v
+	}
+}
+
+func isEmpty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
+
+// RunOrderGroups returns the actions of a stage grouped by run order, actions of a group run in parallel
+func RunOrderGroups(actions []types.ActionDeclaration) [][]types.ActionDeclaration {
+	groups := map[int32][]types.ActionDeclaration{}
+	for _, action := range actions {
+		groups[RunOrder(action)] = append(groups[RunOrder(action)], action)
+	}
+
+	orders := make([]int32, 0, len(groups))
+	for order := range groups {
+		orders = append(orders, order)
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i] < orders[j] })
+
+	out := make([][]types.ActionDeclaration, 0, len(orders))
+	for _, order := range orders {
+		out = append(out, groups[order])
+	}
+	return out
+}
+
+// RunOrder returns the run order of an action, 1 when not set
+func RunOrder(action types.ActionDeclaration) int32 {
+	if action.RunOrder == nil {
+		return 1
+	}
+	return *action.RunOrder
+}
@@ -0,0 +1,394 @@
+package tui
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+	"github.com/fabio42/codeplumber/models/table"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/pkg/browser"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	maxStackEvents   = 25
+	maxServiceEvents = 10
+	maxS3Objects     = 50
+)
+
+func (m *ActionTable) refresh() {
+	go refreshActionOps(m.ui, m.view, m.resource)
+}
+
+func refreshActionOps(c *uiData, view string, resource PipelineResource) {
+	var rows []table.Row
+	var err error
+
+	c.startSpinner()
+
+	record := fmt.Sprintf("Action-%s-%s-%s.json", resource.PipelineName, resource.StageName, resource.ActionName)
+	if config.Mode.Replay {
+		rows, err = config.Recorder.GetTableRows(record)
+	} else {
+		rows, err = actionRows(resource)
+		if err == nil {
+			config.Recorder.Record(record, rows)
+		}
+	}
+
+	c.stopSpinner()
+	if err != nil {
+		log.Debug().Str("model", "tui").Str("func", "refreshActionOps").Msgf("error: %v", err)
+		c.errorMsg(view, err.Error())
+	}
+	c.updateView(view, rows)
+}
+
+// actionRows returns the details of the action execution followed by the provider specific details
+func actionRows(resource PipelineResource) ([]table.Row, error) {
+	cfg := actionConfig(resource.Region)
+
+	rows, err := actionExecutionRows(resource)
+	if err != nil {
+		return rows, err
+	}
+
+	var details []table.Row
+	switch providerView(resource.Provider, resource.Category) {
+	case cloudformationView:
+		details, err = cloudformationRows(cfg, resource)
+	case ecsView:
+		details, err = ecsRows(cfg, resource)
+	case codedeployView:
+		details, err = codedeployRows(cfg, resource)
+	case lambdaView:
+		details, err = lambdaRows(cfg, resource)
+	case s3View:
+		details, err = s3Rows(cfg, resource)
+	case sourceView:
+		details, err = sourceRows(cfg, resource)
+	}
+	return append(rows, details...), err
+}
+
+// actionConfig returns the AWS configuration to use for cross-region actions
+func actionConfig(region string) aws.Config {
+	cfg := config.AwsConfig.Copy()
+	if region != "" {
+		cfg.Region = region
+	}
+	return cfg
+}
+
+func actionExecutionRows(resource PipelineResource) ([]table.Row, error) {
+	rows := []table.Row{
+		{"Action Name", resource.ActionName},
+		{"Provider", fmt.Sprintf("%v/%v", resource.Provider, resource.Category)},
+		{"Stage Name", resource.StageName},
+		{"Status", resource.Status},
+	}
+
+	configuration := resource.Configuration
+	var outputVariables map[string]string
+	if resource.PipelineExecutionID != "" {
+		detail, err := awsqueries.GetActionExecution(config.AwsConfig, resource.PipelineName, resource.PipelineExecutionID, resource.StageName, resource.ActionName)
+		if err != nil {
+			return rows, err
+		}
+
+		rows = append(rows, table.Row{"Started", printOptionalTime(detail.StartTime)})
+		rows = append(rows, table.Row{"Last Update", printOptionalTime(detail.LastUpdateTime)})
+		if detail.Input != nil && len(detail.Input.ResolvedConfiguration) > 0 {
+			configuration = detail.Input.ResolvedConfiguration
+		}
+		if result := detail.Output; result != nil {
+			outputVariables = result.OutputVariables
+			if r := result.ExecutionResult; r != nil {
+				rows = append(rows, table.Row{"Summary", aws.ToString(r.ExternalExecutionSummary)})
+				rows = append(rows, table.Row{"External ID", aws.ToString(r.ExternalExecutionId)})
+				if r.ExternalExecutionUrl != nil {
+					rows = append(rows, table.Row{"URL", *r.ExternalExecutionUrl})
+				}
+				if r.ErrorDetails != nil {
+					rows = append(rows, table.Row{"Error", aws.ToString(r.ErrorDetails.Message)})
+				}
+			}
+		}
+	}
+
+	rows = append(rows, table.Row{"", ""})
+	rows = append(rows, table.Row{"Configuration:", ""})
+	rows = append(rows, mapRows(configuration)...)
+
+	if len(outputVariables) > 0 {
+		rows = append(rows, table.Row{"", ""})
+		rows = append(rows, table.Row{"Output Variables:", ""})
+		rows = append(rows, mapRows(outputVariables)...)
+	}
+	return rows, nil
+}
+
+func cloudformationRows(cfg aws.Config, resource PipelineResource) ([]table.Row, error) {
+	stackName := resource.Configuration["StackName"]
+	data, err := awsqueries.GetCloudFormationData(cfg, stackName, "", maxStackEvents)
+	if err != nil {
+		return nil, err
+	}
+	stack := data.Stack
+
+	rows := []table.Row{
+		{"", ""},
+		{"CloudFormation Stack:", ""},
+		{"  Stack Name", aws.ToString(stack.StackName)},
+		{"  Stack Status", string(stack.StackStatus)},
+		{"  Status Reason", aws.ToString(stack.StackStatusReason)},
+		{"  Last Updated", printOptionalTime(stack.LastUpdatedTime)},
+		{"  Console URL", awsqueries.ConsoleURL(cfg.Region, fmt.Sprintf(
+			"/cloudformation/home?region=%v#/stacks/stackinfo?stackId=%v",
+			cfg.Region, aws.ToString(stack.StackId)))},
+	}
+
+	if changeSetName := resource.Configuration["ChangeSetName"]; changeSetName != "" {
+		rows = append(rows, table.Row{"", ""})
+		rows = append(rows, table.Row{"Change Set:", ""})
+		rows = append(rows, table.Row{"  Name", changeSetName})
+		// The change set is removed once executed, this is not an error
+		changeSet, err := awsqueries.GetCloudFormationChangeSet(cfg, stackName, changeSetName)
+		if err != nil {
+			rows = append(rows, table.Row{"  Status", "Not found"})
+		} else {
+			rows = append(rows, table.Row{"  Status", string(changeSet.Status)})
+			rows = append(rows, table.Row{"  Execution Status", string(changeSet.ExecutionStatus)})
+			rows = append(rows, table.Row{"  Resource Changes", strconv.Itoa(len(changeSet.Changes))})
+		}
+	}
+
+	rows = append(rows, table.Row{"", ""})
+	rows = append(rows, table.Row{"Stack Events:", ""})
+	for _, event := range data.Events {
+		rows = append(rows, table.Row{
+			"  " + printOptionalTime(event.Timestamp),
+			strings.TrimSpace(fmt.Sprintf("%v %v %v",
+				event.ResourceStatus,
+				aws.ToString(event.LogicalResourceId),
+				aws.ToString(event.ResourceStatusReason))),
+		})
+	}
+	return rows, nil
+}
+
+func ecsRows(cfg aws.Config, resource PipelineResource) ([]table.Row, error) {
+	clusterName := resource.Configuration["ClusterName"]
+	serviceName := resource.Configuration["ServiceName"]
+	data, err := awsqueries.GetECSServiceData(cfg, clusterName, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	service := data.Service
+
+	rows := []table.Row{
+		{"", ""},
+		{"ECS Service:", ""},
+		{"  Cluster Name", clusterName},
+		{"  Service Name", serviceName},
+		{"  Status", aws.ToString(service.Status)},
+		{"  Tasks", fmt.Sprintf("desired: %v, running: %v, pending: %v", service.DesiredCount, service.RunningCount, service.PendingCount)},
+		{"  Task Definition", path.Base(aws.ToString(service.TaskDefinition))},
+		{"  Console URL", awsqueries.ConsoleURL(cfg.Region, fmt.Sprintf(
+			"/ecs/v2/clusters/%v/services/%v/health?region=%v",
+			clusterName, serviceName, cfg.Region))},
+		{"", ""},
+		{"Deployments:", ""},
+	}
+
+	for _, deployment := range service.Deployments {
+		rows = append(rows, table.Row{
+			"  " + aws.ToString(deployment.Id),
+			fmt.Sprintf("%v %v running: %v/%v failed: %v %v",
+				aws.ToString(deployment.Status),
+				deployment.RolloutState,
+				deployment.RunningCount,
+				deployment.DesiredCount,
+				deployment.FailedTasks,
+				path.Base(aws.ToString(deployment.TaskDefinition))),
+		})
+	}
+
+	rows = append(rows, table.Row{"", ""})
+	rows = append(rows, table.Row{"Tasks:", ""})
+	for _, task := range data.Tasks {
+		rows = append(rows, table.Row{
+			"  " + path.Base(aws.ToString(task.TaskArn)),
+			fmt.Sprintf("%v %v %v %v",
+				aws.ToString(task.LastStatus),
+				task.HealthStatus,
+				path.Base(aws.ToString(task.TaskDefinitionArn)),
+				aws.ToString(task.StoppedReason)),
+		})
+	}
+
+	rows = append(rows, table.Row{"", ""})
+	rows = append(rows, table.Row{"Events:", ""})
+	for i, event := range service.Events {
+		if i == maxServiceEvents {
+			break
+		}
+		rows = append(rows, table.Row{"  " + printOptionalTime(event.CreatedAt), aws.ToString(event.Message)})
+	}
+	return rows, nil
+}
+
+func codedeployRows(cfg aws.Config, resource PipelineResource) ([]table.Row, error) {
+	if resource.ExternalExecutionID == "" {
+		return []table.Row{{"", ""}, {"CodeDeploy Deployment:", "Not started"}}, nil
+	}
+
+	data, err := awsqueries.GetCodeDeployData(cfg, resource.ExternalExecutionID)
+	if err != nil {
+		return nil, err
+	}
+	deployment := data.Deployment
+
+	rows := []table.Row{
+		{"", ""},
+		{"CodeDeploy Deployment:", ""},
+		{"  Deployment ID", aws.ToString(deployment.DeploymentId)},
+		{"  Application", aws.ToString(deployment.ApplicationName)},
+		{"  Deployment Group", aws.ToString(deployment.DeploymentGroupName)},
+		{"  Deployment Config", aws.ToString(deployment.DeploymentConfigName)},
+		{"  Status", string(deployment.Status)},
+		{"  Created", printOptionalTime(deployment.CreateTime)},
+		{"  Completed", printOptionalTime(deployment.CompleteTime)},
+	}
+	if o := deployment.DeploymentOverview; o != nil {
+		rows = append(rows, table.Row{"  Overview", fmt.Sprintf(
+			"succeeded: %v, failed: %v, in progress: %v, pending: %v, skipped: %v",
+			o.Succeeded, o.Failed, o.InProgress, o.Pending, o.Skipped)})
+	}
+	if deployment.ErrorInformation != nil {
+		rows = append(rows, table.Row{"  Error", aws.ToString(deployment.ErrorInformation.Message)})
+	}
+	rows = append(rows, table.Row{"  Console URL", awsqueries.ConsoleURL(cfg.Region, fmt.Sprintf(
+		"/codesuite/codedeploy/deployments/%v?region=%v",
+		aws.ToString(deployment.DeploymentId), cfg.Region))})
+
+	rows = append(rows, table.Row{"", ""})
+	rows = append(rows, table.Row{"Targets:", ""})
+	for _, target := range data.Targets {
+		var id, status string
+		var lastUpdate *time.Time
+		switch {
+		case target.InstanceTarget != nil:
+			id, status, lastUpdate = aws.ToString(target.InstanceTarget.TargetId), string(target.InstanceTarget.Status), target.InstanceTarget.LastUpdatedAt
+		case target.EcsTarget != nil:
+			id, status, lastUpdate = aws.ToString(target.EcsTarget.TargetId), string(target.EcsTarget.Status), target.EcsTarget.LastUpdatedAt
+		case target.LambdaTarget != nil:
+			id, status, lastUpdate = aws.ToString(target.LambdaTarget.TargetId), string(target.LambdaTarget.Status), target.LambdaTarget.LastUpdatedAt
+		case target.CloudFormationTarget != nil:
+			id, status, lastUpdate = aws.ToString(target.CloudFormationTarget.TargetId), string(target.CloudFormationTarget.Status), target.CloudFormationTarget.LastUpdatedAt
+		}
+		rows = append(rows, table.Row{"  " + id, fmt.Sprintf("%v %v", status, printOptionalTime(lastUpdate))})
+	}
+	return rows, nil
+}
+
+func lambdaRows(cfg aws.Config, resource PipelineResource) ([]table.Row, error) {
+	functionName := resource.Configuration["FunctionName"]
+	function, err := awsqueries.GetLambdaFunction(cfg, functionName)
+	if err != nil {
+		return nil, err
+	}
+
+	return []table.Row{
+		{"", ""},
+		{"Lambda Function:", ""},
+		{"  Function Name", aws.ToString(function.FunctionName)},
+		{"  Runtime", string(function.Runtime)},
+		{"  Handler", aws.ToString(function.Handler)},
+		{"  State", string(function.State)},
+		{"  Last Modified", aws.ToString(function.LastModified)},
+		{"  Log Group", fmt.Sprintf("/aws/lambda/%v", aws.ToString(function.FunctionName))},
+		{"  Console URL", awsqueries.ConsoleURL(cfg.Region, fmt.Sprintf(
+			"/lambda/home?region=%v#/functions/%v",
+			cfg.Region, aws.ToString(function.FunctionName)))},
+	}, nil
+}
+
+func s3Rows(cfg aws.Config, resource PipelineResource) ([]table.Row, error) {
+	bucket := resource.Configuration["BucketName"]
+	key := resource.Configuration["ObjectKey"]
+	extract := strings.EqualFold(resource.Configuration["Extract"], "true")
+
+	rows := []table.Row{
+		{"", ""},
+		{"S3 Deploy Target:", ""},
+		{"  Bucket Name", bucket},
+		{"  Object Key", key},
+		{"  Extract", strconv.FormatBool(extract)},
+		{"  Console URL", awsqueries.ConsoleURL(cfg.Region, fmt.Sprintf(
+			"/s3/buckets/%v?region=%v&prefix=%v",
+			bucket, cfg.Region, key))},
+	}
+
+	if !extract {
+		object, err := awsqueries.HeadS3Object(cfg, bucket, key)
+		if err != nil {
+			return rows, err
+		}
+		rows = append(rows, table.Row{"  Size", strconv.FormatInt(aws.ToInt64(object.ContentLength), 10)})
+		rows = append(rows, table.Row{"  Last Modified", printOptionalTime(object.LastModified)})
+		rows = append(rows, table.Row{"  ETag", aws.ToString(object.ETag)})
+		return rows, nil
+	}
+
+	objects, err := awsqueries.ListS3Objects(cfg, bucket, key, maxS3Objects)
+	if err != nil {
+		return rows, err
+	}
+	rows = append(rows, table.Row{"", ""})
+	rows = append(rows, table.Row{"Objects:", ""})
+	for _, object := range objects {
+		rows = append(rows, table.Row{
+			"  " + aws.ToString(object.Key),
+			fmt.Sprintf("%v %v", aws.ToInt64(object.Size), printOptionalTime(object.LastModified)),
+		})
+	}
+	return rows, nil
+}
+
+// mapRows returns indented rows of a map sorted by key
+func mapRows(m map[string]string) []table.Row {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	rows := make([]table.Row, len(keys))
+	for i, k := range keys {
+		rows[i] = table.Row{"  " + k, m[k]}
+	}
+	return rows
+}
+
+func (m *ActionTable) browse() {
+	var url string
+	for _, r := range m.Rows() {
+		switch strings.TrimSpace(r[0]) {
+		case "Console URL":
+			browser.OpenURL(r[1])
+			return
+		case "URL":
+			url = r[1]
+		}
+	}
+	if url != "" {
+		browser.OpenURL(url)
+	}
+}
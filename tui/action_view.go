@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/fabio42/codeplumber/models/table"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog/log"
+)
+
+// ActionTable represent the details of a AWS CodePipeline action
+// It is used for all providers not having a dedicated view like CodeBuild
+type ActionTable struct {
+	*table.Model
+	name          string
+	view          string
+	resource      PipelineResource
+	width, height int
+	ui            *uiData
+	help          help.Model
+}
+
+// NewActionTable returns a new ActionTable
+func NewActionTable(ui *uiData) *ActionTable {
+	t := table.New(table.WithKeyMap(tableKeys))
+	t.SetStyles(ui.getTablePatchedStyle())
+	return &ActionTable{
+		Model: &t,
+		ui:    ui,
+		help:  help.New(),
+	}
+}
+
+// SetColumns set the columns of the ActionTable
+func (m *ActionTable) SetColumns(width int) {
+	cols := make([]table.Column, 2)
+
+	width = width - 5
+	componentSize := percent(width, 40, 25)
+	valueSize := width - componentSize
+
+	cols[0] = table.Column{Title: m.resource.Provider + " option", Width: componentSize}
+	cols[1] = table.Column{Title: "Value", Width: valueSize}
+	m.Model.SetColumns(cols)
+	m.Focus()
+}
+
+// SetWidth set the width of the ActionTable
+func (m *ActionTable) SetWidth(width int) {
+	m.SetColumns(width)
+	m.Model.SetWidth(width)
+}
+
+// Init implement the tea.Model interface
+func (m *ActionTable) Init() tea.Cmd {
+	return nil
+}
+
+// Update implement the tea.Model interface
+func (m *ActionTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m.ui.updatPath(m.name)
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		verticalMarginHeight := 4
+		if m.ui.help {
+			verticalMarginHeight += helpFullHeiggt
+		} else {
+			verticalMarginHeight += helpHeight
+		}
+
+		m.width = msg.Width - 2
+		m.height = msg.Height - verticalMarginHeight
+		m.SetHeight(m.height)
+		m.SetWidth(m.width)
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, allKeys.Previous):
+			m.ui.previousViewWithRefresh()
+
+		case key.Matches(msg, allKeys.Refresh):
+			m.refresh()
+
+		case key.Matches(msg, allKeys.Browse):
+			m.browse()
+		}
+
+	case tuiMsg:
+		log.Debug().Str("model", "tui").Str("func", "ActionTable.Update").Msgf("tuiMsg class: %v, id: %v, trigger: %v, data: %v", msg.class, msg.id, msg.trigger, msg.data)
+		switch msg.class {
+		case viewChange:
+			m.SetRows([]table.Row{})
+			m.resource = msg.data.(PipelineResource)
+			m.view = msg.id
+			m.name = m.resource.ActionName
+			m.ui.updatPath(m.name)
+			m.SetColumns(m.width)
+			m.refresh()
+
+		case viewUpdate:
+			rows := msg.data.([]table.Row)
+			m.SetColumns(m.width)
+			m.SetRows(rows)
+		}
+	}
+
+	*m.Model, _ = m.Model.Update(msg)
+	return m, nil
+}
+
+// View implement the tea.Model interface
+func (m *ActionTable) View() string {
+	var help string
+	if m.ui.help {
+		help = m.helpViewFull()
+	} else {
+		help = m.helpView()
+	}
+
+	return fmt.Sprintf("%s\n%s", m.Model.View(), help)
+}
+
+func (m *ActionTable) helpView() string {
+	return m.help.ShortHelpView([]key.Binding{
+		allKeys.Previous,
+		allKeys.Refresh,
+		allKeys.Browse,
+		allKeys.Help,
+	})
+}
+
+func (m *ActionTable) helpViewFull() string {
+	return m.help.FullHelpView([][]key.Binding{
+		{
+			allKeys.Up,
+			allKeys.Down,
+			allKeys.Previous,
+		},
+		{
+			allKeys.Browse,
+		},
+		{
+			allKeys.Refresh,
+			allKeys.Quit,
+			allKeys.Help,
+		},
+	})
+}
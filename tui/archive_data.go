@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+	"github.com/fabio42/codeplumber/models/table"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (m *ArchiveTable) refresh() {
+	go refreshArchiveOps(m.ui, m.artifact)
+}
+
+func refreshArchiveOps(c *uiData, artifact ArtifactSelector) {
+	var rows []table.Row
+	var err error
+
+	c.startSpinner()
+
+	record := fmt.Sprintf("Archive-%s.json", artifact.Name)
+	if config.Mode.Replay {
+		rows, err = config.Recorder.GetTableRows(record)
+	} else {
+		rows, err = archiveRows(artifact)
+		if err == nil {
+			config.Recorder.Record(record, rows)
+		}
+	}
+
+	c.stopSpinner()
+	if err != nil {
+		log.Debug().Str("model", "tui").Str("func", "refreshArchiveOps").Msgf("error: %v", err)
+		c.errorMsg(archiveView, err.Error())
+	}
+	c.updateView(archiveView, rows)
+}
+
+func archiveRows(artifact ArtifactSelector) ([]table.Row, error) {
+	files, err := awsqueries.ListS3ArchiveFiles(actionConfig(artifact.Region), artifact.Bucket, artifact.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]table.Row, 0, len(files))
+	for _, f := range files {
+		rows = append(rows, table.Row{
+			f.Name,
+			strconv.FormatUint(f.Size, 10),
+			printOptionalTime(&f.Modified),
+		})
+	}
+	return rows, nil
+}
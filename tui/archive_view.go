@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/fabio42/codeplumber/models/table"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog/log"
+)
+
+// ArchiveTable represent the content of a CodePipeline artifact archive
+type ArchiveTable struct {
+	*table.Model
+	artifact      ArtifactSelector
+	width, height int
+	ui            *uiData
+	help          help.Model
+}
+
+// NewArchiveTable returns a new ArchiveTable
+func NewArchiveTable(ui *uiData) *ArchiveTable {
+	t := table.New(table.WithKeyMap(tableKeys))
+	t.SetStyles(ui.getTablePatchedStyle())
+	return &ArchiveTable{
+		Model: &t,
+		ui:    ui,
+		help:  help.New(),
+	}
+}
+
+// SetColumns set the columns of the ArchiveTable
+func (m *ArchiveTable) SetColumns(width int) {
+	cols := make([]table.Column, 3)
+
+	width = width - 3
+	sizeSize := percent(width, 10, 12)
+	modifiedSize := percent(width, 20, 22)
+	nameSize := width - sizeSize - modifiedSize
+
+	cols[0] = table.Column{Title: "File", Width: nameSize}
+	cols[1] = table.Column{Title: "Size", Width: sizeSize}
+	cols[2] = table.Column{Title: "Modified", Width: modifiedSize}
+	m.Model.SetColumns(cols)
+	m.Focus()
+}
+
+// SetWidth set the width of the ArchiveTable
+func (m *ArchiveTable) SetWidth(width int) {
+	m.SetColumns(width)
+	m.Model.SetWidth(width)
+}
+
+// Init implement the tea.Model interface
+func (m *ArchiveTable) Init() tea.Cmd {
+	return nil
+}
+
+// Update implement the tea.Model interface
+func (m *ArchiveTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m.ui.updatPath(m.artifact.Name)
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		verticalMarginHeight := 4
+		if m.ui.help {
+			verticalMarginHeight += helpFullHeiggt
+		} else {
+			verticalMarginHeight += helpHeight
+		}
+
+		m.width = msg.Width - 2
+		m.height = msg.Height - verticalMarginHeight
+		m.SetHeight(m.height)
+		m.SetWidth(m.width)
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, allKeys.Previous):
+			m.ui.previousView()
+
+		case key.Matches(msg, allKeys.Refresh):
+			m.refresh()
+
+		case key.Matches(msg, artifactKeys.Download):
+			m.ui.requestInput(artifactDownload, "text", fmt.Sprintf("DOWNLOAD %v to directory (empty to cancel):", m.artifact.Name), m.artifact)
+
+		case key.Matches(msg, artifactKeys.Extract):
+			m.ui.requestInput(artifactExtract, "text", fmt.Sprintf("EXTRACT %v to directory (empty to cancel):", m.artifact.Name), m.artifact)
+		}
+
+	case tuiMsg:
+		log.Debug().Str("model", "tui").Str("func", "ArchiveTable.Update").Msgf("tuiMsg class: %v, id: %v, trigger: %v, data: %v", msg.class, msg.id, msg.trigger, msg.data)
+		switch msg.class {
+		case viewChange:
+			m.SetRows([]table.Row{})
+			m.artifact = msg.data.(ArtifactSelector)
+			m.ui.updatPath(m.artifact.Name)
+			m.SetColumns(m.width)
+			m.refresh()
+
+		case viewUpdate:
+			rows := msg.data.([]table.Row)
+			m.SetColumns(m.width)
+			m.SetRows(rows)
+
+		case response:
+			if msg.trigger {
+				switch msg.src {
+				case artifactDownload:
+					go m.ui.downloadArtifact(archiveView, msg.reference.(ArtifactSelector), msg.data.(string))
+				case artifactExtract:
+					go m.ui.extractArtifact(archiveView, msg.reference.(ArtifactSelector), msg.data.(string))
+				}
+			}
+		}
+	}
+
+	*m.Model, _ = m.Model.Update(msg)
+	return m, nil
+}
+
+// View implement the tea.Model interface
+func (m *ArchiveTable) View() string {
+	var help string
+	if m.ui.help {
+		help = m.helpViewFull()
+	} else {
+		help = m.helpView()
+	}
+
+	return fmt.Sprintf("%s\n%s", m.Model.View(), help)
+}
+
+func (m *ArchiveTable) helpView() string {
+	return m.help.ShortHelpView([]key.Binding{
+		allKeys.Previous,
+		artifactKeys.Download,
+		artifactKeys.Extract,
+		allKeys.Help,
+	})
+}
+
+func (m *ArchiveTable) helpViewFull() string {
+	return m.help.FullHelpView([][]key.Binding{
+		{
+			allKeys.Up,
+			allKeys.Down,
+			allKeys.Previous,
+		},
+		{
+			artifactKeys.Download,
+			artifactKeys.Extract,
+		},
+		{
+			allKeys.Refresh,
+			allKeys.Quit,
+			allKeys.Help,
+		},
+	})
+}
@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+	"github.com/fabio42/codeplumber/models/table"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline/types"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	artifactInput  = "Input"
+	artifactOutput = "Output"
+)
+
+// ArtifactSelector describe a CodePipeline artifact stored in S3
+type ArtifactSelector struct {
+	Name   string
+	Bucket string
+	Key    string
+	Region string
+}
+
+func (m *ArtifactsTable) refresh() {
+	go refreshArtifactsOps(m.ui, m.resource)
+}
+
+func refreshArtifactsOps(c *uiData, resource PipelineResource) {
+	var rows []table.Row
+	var err error
+
+	c.startSpinner()
+
+	record := fmt.Sprintf("Artifacts-%s-%s-%s.json", resource.PipelineName, resource.StageName, resource.ActionName)
+	if config.Mode.Replay {
+		rows, err = config.Recorder.GetTableRows(record)
+	} else {
+		rows, err = artifactsRows(resource)
+		if err == nil {
+			config.Recorder.Record(record, rows)
+		}
+	}
+
+	c.stopSpinner()
+	if err != nil {
+		log.Debug().Str("model", "tui").Str("func", "refreshArtifactsOps").Msgf("error: %v", err)
+		c.errorMsg(artifactsView, err.Error())
+	}
+	c.updateView(artifactsView, rows)
+}
+
+func artifactsRows(resource PipelineResource) ([]table.Row, error) {
+	if resource.PipelineExecutionID == "" {
+		return nil, fmt.Errorf("%v has not been executed yet", resource.ActionName)
+	}
+
+	detail, err := awsqueries.GetActionExecution(config.AwsConfig, resource.PipelineName, resource.PipelineExecutionID, resource.StageName, resource.ActionName)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []table.Row
+	cfg := actionConfig(resource.Region)
+	if detail.Input != nil {
+		rows = append(rows, artifactRows(cfg, artifactInput, detail.Input.InputArtifacts)...)
+	}
+	if detail.Output != nil {
+		rows = append(rows, artifactRows(cfg, artifactOutput, detail.Output.OutputArtifacts)...)
+	}
+	return rows, nil
+}
+
+func artifactRows(cfg aws.Config, direction string, artifacts []types.ArtifactDetail) []table.Row {
+	rows := make([]table.Row, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		if artifact.S3location == nil {
+			continue
+		}
+		bucket := aws.ToString(artifact.S3location.Bucket)
+		key := aws.ToString(artifact.S3location.Key)
+
+		size, lastModified := "N/A", "N/A"
+		// Artifacts are removed from S3 based on the artifact store lifecycle
+		if object, err := awsqueries.HeadS3Object(cfg, bucket, key); err == nil {
+			size = strconv.FormatInt(aws.ToInt64(object.ContentLength), 10)
+			lastModified = printOptionalTime(object.LastModified)
+		}
+
+		rows = append(rows, table.Row{
+			direction,
+			aws.ToString(artifact.Name),
+			fmt.Sprintf("s3://%v/%v", bucket, key),
+			size,
+			lastModified,
+		})
+	}
+	return rows
+}
+
+// artifactSelector returns the artifact of a row
+func (m *ArtifactsTable) artifactSelector(row table.Row) (ArtifactSelector, error) {
+	bucket, key, err := awsqueries.ParseS3Location(row[2])
+	return ArtifactSelector{
+		Name:   row[1],
+		Bucket: bucket,
+		Key:    key,
+		Region: m.resource.Region,
+	}, err
+}
+
+// downloadArtifact saves the artifact archive in dir
+func (c *uiData) downloadArtifact(src string, artifact ArtifactSelector, dir string) {
+	c.startSpinner()
+	dst := filepath.Join(expandHome(dir), artifact.Name+".zip")
+	err := awsqueries.DownloadS3Object(actionConfig(artifact.Region), artifact.Bucket, artifact.Key, dst)
+	c.stopSpinner()
+	if err != nil {
+		c.errorMsg(src, fmt.Sprintf("failed to download %v: %v", artifact.Name, err))
+		return
+	}
+	c.infoMsg(src, fmt.Sprintf("%v saved to %v", artifact.Name, dst))
+}
+
+// extractArtifact extracts the artifact archive in dir
+func (c *uiData) extractArtifact(src string, artifact ArtifactSelector, dir string) {
+	c.startSpinner()
+	dst := expandHome(dir)
+	err := awsqueries.ExtractS3Archive(actionConfig(artifact.Region), artifact.Bucket, artifact.Key, dst)
+	c.stopSpinner()
+	if err != nil {
+		c.errorMsg(src, fmt.Sprintf("failed to extract %v: %v", artifact.Name, err))
+		return
+	}
+	c.infoMsg(src, fmt.Sprintf("%v extracted to %v", artifact.Name, dst))
+}
+
+func expandHome(path string) string {
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(path, "~/") {
+		return filepath.Join(home, path[2:])
+	}
+	return os.ExpandEnv(path)
+}
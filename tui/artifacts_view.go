@@ -0,0 +1,202 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/fabio42/codeplumber/models/table"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	artifactDownload = "artifactDownload"
+	artifactExtract  = "artifactExtract"
+)
+
+// ArtifactsTable represent the input and output artifacts of a AWS CodePipeline action
+type ArtifactsTable struct {
+	*table.Model
+	name          string
+	resource      PipelineResource
+	width, height int
+	ui            *uiData
+	help          help.Model
+}
+
+// NewArtifactsTable returns a new ArtifactsTable
+func NewArtifactsTable(ui *uiData) *ArtifactsTable {
+	t := table.New(table.WithKeyMap(tableKeys))
+	t.SetStyles(ui.getTablePatchedStyle())
+	return &ArtifactsTable{
+		Model: &t,
+		ui:    ui,
+		help:  help.New(),
+	}
+}
+
+// SetColumns set the columns of the ArtifactsTable
+func (m *ArtifactsTable) SetColumns(width int) {
+	cols := make([]table.Column, 5)
+
+	width = width - 5
+	directionSize := percent(width, 10, 8)
+	nameSize := percent(width, 20, 30)
+	sizeSize := percent(width, 10, 12)
+	lastModifiedSize := percent(width, 20, 22)
+	locationSize := width - directionSize - nameSize - sizeSize - lastModifiedSize
+
+	cols[0] = table.Column{Title: "Direction", Width: directionSize}
+	cols[1] = table.Column{Title: "Artifact Name", Width: nameSize}
+	cols[2] = table.Column{Title: "S3 Location", Width: locationSize}
+	cols[3] = table.Column{Title: "Size", Width: sizeSize}
+	cols[4] = table.Column{Title: "Last modified", Width: lastModifiedSize}
+	m.Model.SetColumns(cols)
+	m.Focus()
+}
+
+// SetWidth set the width of the ArtifactsTable
+func (m *ArtifactsTable) SetWidth(width int) {
+	m.SetColumns(width)
+	m.Model.SetWidth(width)
+}
+
+// Init implement the tea.Model interface
+func (m *ArtifactsTable) Init() tea.Cmd {
+	return nil
+}
+
+// Update implement the tea.Model interface
+func (m *ArtifactsTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m.ui.updatPath(m.name)
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		verticalMarginHeight := 4
+		if m.ui.help {
+			verticalMarginHeight += helpFullHeiggt
+		} else {
+			verticalMarginHeight += helpHeight
+		}
+
+		m.width = msg.Width - 2
+		m.height = msg.Height - verticalMarginHeight
+		m.SetHeight(m.height)
+		m.SetWidth(m.width)
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, allKeys.Previous):
+			m.ui.previousView()
+
+		case key.Matches(msg, allKeys.Refresh):
+			m.refresh()
+
+		case key.Matches(msg, allKeys.Select):
+			m.open()
+
+		case key.Matches(msg, artifactKeys.Download):
+			if a, err := m.selected(); err == nil {
+				m.ui.requestInput(artifactDownload, "text", fmt.Sprintf("DOWNLOAD %v to directory (empty to cancel):", a.Name), a)
+			}
+
+		case key.Matches(msg, artifactKeys.Extract):
+			if a, err := m.selected(); err == nil {
+				m.ui.requestInput(artifactExtract, "text", fmt.Sprintf("EXTRACT %v to directory (empty to cancel):", a.Name), a)
+			}
+		}
+
+	case table.SelectMsg:
+		m.open()
+
+	case tuiMsg:
+		log.Debug().Str("model", "tui").Str("func", "ArtifactsTable.Update").Msgf("tuiMsg class: %v, id: %v, trigger: %v, data: %v", msg.class, msg.id, msg.trigger, msg.data)
+		switch msg.class {
+		case viewChange:
+			m.SetRows([]table.Row{})
+			m.resource = msg.data.(PipelineResource)
+			m.name = m.resource.ActionName + "-artifacts"
+			m.ui.updatPath(m.name)
+			m.SetColumns(m.width)
+			m.refresh()
+
+		case viewUpdate:
+			rows := msg.data.([]table.Row)
+			m.SetColumns(m.width)
+			m.SetRows(rows)
+
+		case response:
+			if msg.trigger {
+				switch msg.src {
+				case artifactDownload:
+					go m.ui.downloadArtifact(artifactsView, msg.reference.(ArtifactSelector), msg.data.(string))
+				case artifactExtract:
+					go m.ui.extractArtifact(artifactsView, msg.reference.(ArtifactSelector), msg.data.(string))
+				}
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	*m.Model, cmd = m.Model.Update(msg)
+	return m, cmd
+}
+
+// open lists the files of the selected artifact
+func (m *ArtifactsTable) open() {
+	if a, err := m.selected(); err == nil {
+		m.ui.changeView(artifactsView, archiveView, a)
+	}
+}
+
+func (m *ArtifactsTable) selected() (ArtifactSelector, error) {
+	row := m.SelectedRow()
+	if len(row) == 0 {
+		return ArtifactSelector{}, fmt.Errorf("no artifact selected")
+	}
+	return m.artifactSelector(row)
+}
+
+// View implement the tea.Model interface
+func (m *ArtifactsTable) View() string {
+	var help string
+	if m.ui.help {
+		help = m.helpViewFull()
+	} else {
+		help = m.helpView()
+	}
+
+	return fmt.Sprintf("%s\n%s", m.Model.View(), help)
+}
+
+func (m *ArtifactsTable) helpView() string {
+	return m.help.ShortHelpView([]key.Binding{
+		allKeys.Select,
+		allKeys.Previous,
+		artifactKeys.Download,
+		artifactKeys.Extract,
+		allKeys.Help,
+	})
+}
+
+func (m *ArtifactsTable) helpViewFull() string {
+	return m.help.FullHelpView([][]key.Binding{
+		{
+			allKeys.Up,
+			allKeys.Down,
+			allKeys.Select,
+			allKeys.Previous,
+		},
+		{
+			artifactKeys.Download,
+			artifactKeys.Extract,
+		},
+		{
+			allKeys.Refresh,
+			allKeys.Quit,
+			allKeys.Help,
+		},
+	})
+}
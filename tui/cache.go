@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+
+	"github.com/rs/zerolog/log"
+)
+
+// pipelinesCache is the on-disk cache of the pipelines listed in an account and region
+// It is shared by all the profiles targeting the same account and region
+type pipelinesCache struct {
+	Pipelines map[string]cachedPipeline
+}
+
+type cachedPipeline struct {
+	Pipeline awsqueries.Pipeline
+	Updated  time.Time
+}
+
+func pipelinesCacheFile() string {
+	if config.CacheDir == "" || config.awsAccountID == "" || config.Mode.Replay {
+		return ""
+	}
+	return filepath.Join(config.CacheDir, fmt.Sprintf("pipelines-%v-%v.json", config.awsAccountID, config.AwsConfig.Region))
+}
+
+// readPipelinesCache returns the cached pipelines not older than the cache TTL
+func readPipelinesCache() pipelinesCache {
+	cache := pipelinesCache{Pipelines: map[string]cachedPipeline{}}
+
+	path := pipelinesCacheFile()
+	if path == "" {
+		return cache
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Debug().Str("model", "tui").Str("func", "readPipelinesCache").Msgf("failed to read cache %v: %v", path, err)
+		}
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil || cache.Pipelines == nil {
+		log.Debug().Str("model", "tui").Str("func", "readPipelinesCache").Msgf("ignoring invalid cache %v: %v", path, err)
+		return pipelinesCache{Pipelines: map[string]cachedPipeline{}}
+	}
+
+	for name, cached := range cache.Pipelines {
+		if config.CacheTTL > 0 && time.Since(cached.Updated) > config.CacheTTL {
+			delete(cache.Pipelines, name)
+		}
+	}
+	return cache
+}
+
+// cachedPipelines returns the cached pipelines matching the filters of the session
+func cachedPipelines() map[string]awsqueries.Pipeline {
+	pipelines := map[string]awsqueries.Pipeline{}
+	for name, cached := range readPipelinesCache().Pipelines {
+		if cacheMatch(cached.Pipeline) {
+			pipelines[name] = cached.Pipeline
+		}
+	}
+	return pipelines
+}
+
+// savePipelinesCache replaces the cached pipelines matching the filters of the session
+// Cached pipelines missing from a fresh listing have been deleted or don't match the filters anymore
+func savePipelinesCache(pipelines map[string]awsqueries.Pipeline) {
+	path := pipelinesCacheFile()
+	if path == "" {
+		return
+	}
+
+	cache := readPipelinesCache()
+	for name, cached := range cache.Pipelines {
+		if cacheMatch(cached.Pipeline) {
+			delete(cache.Pipelines, name)
+		}
+	}
+
+	now := time.Now()
+	for name, pipeline := range pipelines {
+		if pipeline.Error != "" {
+			continue
+		}
+		// Pipeline definitions and states are always fetched when a pipeline is opened
+		pipeline.Data = nil
+		pipeline.StateData = nil
+		cache.Pipelines[name] = cachedPipeline{Pipeline: pipeline, Updated: now}
+	}
+
+	if err := writeJSONFile(path, cache); err != nil {
+		log.Debug().Str("model", "tui").Str("func", "savePipelinesCache").Msgf("failed to write cache %v: %v", path, err)
+	}
+}
+
+func cacheMatch(pipeline awsqueries.Pipeline) bool {
+	if !config.NameFilter.Match(pipeline.PipelineName) {
+		return false
+	}
+	return len(config.TagFilter) == 0 || (pipeline.Tags != nil && config.TagFilter.Match(pipeline.Tags))
+}
+
+// writeJSONFile writes a file in a temporary file renamed once complete, concurrent sessions never read a partial file
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
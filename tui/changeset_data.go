@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+	"github.com/fabio42/codeplumber/models/table"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cfntypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/rs/zerolog/log"
+)
+
+func (m *ChangeSetTable) refresh() {
+	go refreshChangeSetOps(m.ui, m.resource)
+}
+
+func refreshChangeSetOps(c *uiData, resource PipelineResource) {
+	var rows []table.Row
+	var err error
+
+	c.startSpinner()
+
+	record := fmt.Sprintf("ChangeSet-%s-%s-%s.json", resource.PipelineName, resource.StageName, resource.ActionName)
+	if config.Mode.Replay {
+		rows, err = config.Recorder.GetTableRows(record)
+	} else {
+		rows, err = changeSetRows(resource)
+		if err == nil {
+			config.Recorder.Record(record, rows)
+		}
+	}
+
+	c.stopSpinner()
+	if err != nil {
+		log.Debug().Str("model", "tui").Str("func", "refreshChangeSetOps").Msgf("error: %v", err)
+		c.errorMsg(changesetView, err.Error())
+	}
+	c.updateView(changesetView, rows)
+}
+
+func changeSetRows(resource PipelineResource) ([]table.Row, error) {
+	cfg := actionConfig(resource.Region)
+	changeSet, err := awsqueries.GetCloudFormationChangeSet(cfg, resource.Configuration["StackName"], resource.Configuration["ChangeSetName"])
+	if err != nil {
+		return nil, err
+	}
+	if changeSet.Status == cfntypes.ChangeSetStatusFailed {
+		err = fmt.Errorf("change set %v failed: %v", aws.ToString(changeSet.ChangeSetName), aws.ToString(changeSet.StatusReason))
+	}
+
+	rows := make([]table.Row, 0, len(changeSet.Changes))
+	for _, change := range changeSet.Changes {
+		rc := change.ResourceChange
+		if rc == nil {
+			continue
+		}
+		scope := make([]string, len(rc.Scope))
+		for i, s := range rc.Scope {
+			scope[i] = string(s)
+		}
+		rows = append(rows, table.Row{
+			string(rc.Action),
+			aws.ToString(rc.LogicalResourceId),
+			aws.ToString(rc.PhysicalResourceId),
+			aws.ToString(rc.ResourceType),
+			string(rc.Replacement),
+			strings.Join(scope, ", "),
+		})
+	}
+	return rows, err
+}
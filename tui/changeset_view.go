@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/fabio42/codeplumber/models/table"
+
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline/types"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog/log"
+)
+
+// ChangeSetTable represent the resource changes of a AWS CloudFormation change set
+type ChangeSetTable struct {
+	*table.Model
+	name          string
+	resource      PipelineResource
+	width, height int
+	ui            *uiData
+	help          help.Model
+}
+
+// NewChangeSetTable returns a new ChangeSetTable
+func NewChangeSetTable(ui *uiData) *ChangeSetTable {
+	t := table.New(table.WithKeyMap(tableKeys))
+	t.SetStyles(ui.getTablePatchedStyle())
+	return &ChangeSetTable{
+		Model: &t,
+		ui:    ui,
+		help:  help.New(),
+	}
+}
+
+// SetColumns set the columns of the ChangeSetTable
+func (m *ChangeSetTable) SetColumns(width int) {
+	cols := make([]table.Column, 6)
+
+	width = width - 5
+	actionSize := percent(width, 10, 8)
+	replacementSize := percent(width, 10, 12)
+	typeSize := percent(width, 25, 35)
+	physicalSize := percent(width, 20, 40)
+	scopeSize := percent(width, 15, 20)
+	logicalSize := width - actionSize - replacementSize - typeSize - physicalSize - scopeSize
+
+	cols[0] = table.Column{Title: "Action", Width: actionSize}
+	cols[1] = table.Column{Title: "Logical ID", Width: logicalSize}
+	cols[2] = table.Column{Title: "Physical ID", Width: physicalSize}
+	cols[3] = table.Column{Title: "Resource Type", Width: typeSize}
+	cols[4] = table.Column{Title: "Replacement", Width: replacementSize}
+	cols[5] = table.Column{Title: "Scope", Width: scopeSize}
+	m.Model.SetColumns(cols)
+	m.Focus()
+}
+
+// SetWidth set the width of the ChangeSetTable
+func (m *ChangeSetTable) SetWidth(width int) {
+	m.SetColumns(width)
+	m.Model.SetWidth(width)
+}
+
+// Init implement the tea.Model interface
+func (m *ChangeSetTable) Init() tea.Cmd {
+	return nil
+}
+
+// Update implement the tea.Model interface
+func (m *ChangeSetTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m.ui.updatPath(m.name)
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		verticalMarginHeight := 4
+		if m.ui.help {
+			verticalMarginHeight += helpFullHeiggt
+		} else {
+			verticalMarginHeight += helpHeight
+		}
+
+		m.width = msg.Width - 2
+		m.height = msg.Height - verticalMarginHeight
+		m.SetHeight(m.height)
+		m.SetWidth(m.width)
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, allKeys.Previous):
+			m.ui.previousViewWithRefresh()
+
+		case key.Matches(msg, allKeys.Refresh):
+			m.refresh()
+
+		case key.Matches(msg, codePipelineKeys.Approve):
+			if approval, err := m.ui.getApprovalResource(m.resource); err != nil {
+				go m.ui.errorMsg(changesetView, err.Error())
+			} else {
+				m.ui.confirm(approvalApprove, fmt.Sprintf("Approve %v?", approval.ActionName), approval)
+			}
+
+		case key.Matches(msg, codePipelineKeys.Reject):
+			if approval, err := m.ui.getApprovalResource(m.resource); err != nil {
+				go m.ui.errorMsg(changesetView, err.Error())
+			} else {
+				m.ui.requestInput(approvalReject, "reason", fmt.Sprintf("REJECT %v: reason (empty to cancel):", approval.ActionName), approval)
+			}
+		}
+
+	case tuiMsg:
+		log.Debug().Str("model", "tui").Str("func", "ChangeSetTable.Update").Msgf("tuiMsg class: %v, id: %v, trigger: %v, data: %v", msg.class, msg.id, msg.trigger, msg.data)
+		switch msg.class {
+		case viewChange:
+			m.SetRows([]table.Row{})
+			m.resource = msg.data.(PipelineResource)
+			m.name = m.resource.Configuration["ChangeSetName"]
+			m.ui.updatPath(m.name)
+			m.SetColumns(m.width)
+			m.refresh()
+
+		case viewUpdate:
+			rows := msg.data.([]table.Row)
+			m.SetColumns(m.width)
+			m.SetRows(rows)
+
+		case response:
+			if msg.trigger {
+				var err error
+				switch msg.src {
+				case approvalApprove:
+					err = m.ui.approve(msg.reference.(PipelineResource), types.ApprovalStatusApproved, "Approved from codeplumber")
+				case approvalReject:
+					err = m.ui.approve(msg.reference.(PipelineResource), types.ApprovalStatusRejected, msg.data.(string))
+				}
+				if err != nil {
+					go m.ui.errorMsg(changesetView, err.Error())
+				} else {
+					go m.ui.previousViewWithRefresh()
+				}
+			}
+		}
+	}
+
+	*m.Model, _ = m.Model.Update(msg)
+	return m, nil
+}
+
+// View implement the tea.Model interface
+func (m *ChangeSetTable) View() string {
+	var help string
+	if m.ui.help {
+		help = m.helpViewFull()
+	} else {
+		help = m.helpView()
+	}
+
+	return fmt.Sprintf("%s\n%s", m.Model.View(), help)
+}
+
+func (m *ChangeSetTable) helpView() string {
+	return m.help.ShortHelpView([]key.Binding{
+		allKeys.Previous,
+		codePipelineKeys.Approve,
+		codePipelineKeys.Reject,
+		allKeys.Help,
+	})
+}
+
+func (m *ChangeSetTable) helpViewFull() string {
+	return m.help.FullHelpView([][]key.Binding{
+		{
+			allKeys.Up,
+			allKeys.Down,
+			allKeys.Previous,
+		},
+		{
+			codePipelineKeys.Approve,
+			codePipelineKeys.Reject,
+		},
+		{
+			allKeys.Refresh,
+			allKeys.Quit,
+			allKeys.Help,
+		},
+	})
+}
@@ -31,7 +31,7 @@ func refreshCodebuildOps(c *uiData, name, buildID string) {
 		}
 		cb = cbCache[name]
 	} else {
-		cb, err = awsqueries.GetCodeBuildData(config.AwsConfig, config.awsAccountID, name, buildID)
+		cb, err = awsqueries.GetCodeBuildData(config.AwsConfig, name, buildID)
 		if err != nil {
 			log.Fatal().Msgf("error: %v", err)
 		}
@@ -42,23 +42,22 @@ func refreshCodebuildOps(c *uiData, name, buildID string) {
 	build := cb.Builds.Builds[0]
 	project := cb.Project.Projects[0]
 
-	logFriendlyURL := fmt.Sprintf(
-		"https://%v.console.aws.amazon.com/codesuite/codebuild/%v/projects/%v/build/%v/?region=%v",
-		config.AwsConfig.Region,
+	logFriendlyURL := awsqueries.ConsoleURL(config.AwsConfig.Region, fmt.Sprintf(
+		"/codesuite/codebuild/%v/projects/%v/build/%v/?region=%v",
 		config.awsAccountID,
 		*project.Name,
 		*build.Id,
-		config.AwsConfig.Region)
+		config.AwsConfig.Region))
 
 	rows[0] = table.Row{"Project Name", *project.Name}
 	rows[1] = table.Row{"Description", *project.Description}
 	rows[2] = table.Row{"Build Status", string(build.BuildStatus)}
 	rows[3] = table.Row{"Build ID", *build.Id}
-	buildSpecData := ""
-	if strings.HasPrefix(*project.Source.Buildspec, "version:") {
+	buildSpecData := buildspecPath(cb)
+	if isInlineBuildspec(buildSpecData) {
 		buildSpecData = "Inline BuildSpec, press enter to see it"
 	} else {
-		buildSpecData = *project.Source.Buildspec
+		buildSpecData += ", press enter to see it"
 	}
 	rows[4] = table.Row{"BuildSpec", buildSpecData}
 	rows[5] = table.Row{"", ""}
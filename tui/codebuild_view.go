@@ -22,11 +22,13 @@ type CodeBuildTable struct {
 	width, height int
 	ui            *uiData
 	help          help.Model
+	// openLog opens the log once the build is loaded
+	openLog bool
 }
 
 // NewCodeBuildTable returns a new CodeBuildTable
 func NewCodeBuildTable(ui *uiData) *CodeBuildTable {
-	t := table.New()
+	t := table.New(table.WithKeyMap(tableKeys))
 	t.SetStyles(ui.getTablePatchedStyle())
 	return &CodeBuildTable{
 		Model: &t,
@@ -93,14 +95,12 @@ func (m *CodeBuildTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.browse()
 
 		case key.Matches(msg, allKeys.Select):
-			s := m.SelectedRow()
-			if strings.Contains(s[0], "URL") {
-				m.ui.changeView(codebuildView, logView, PagerSelector{name: m.name})
-			} else {
-				m.ui.changeView(codebuildView, buildspecView, PagerSelector{name: m.name, content: s[1]})
-			}
+			m.open()
 		}
 
+	case table.SelectMsg:
+		m.open()
+
 	case tuiMsg:
 		log.Debug().Str("model", "tui").Str("func", "CodeBuildTable.Update").Msgf("tuiMsg class: %v, id: %v, trigger: %v, data: %v", msg.class, msg.id, msg.trigger, msg.data)
 		switch msg.class {
@@ -110,18 +110,39 @@ func (m *CodeBuildTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.pipelineName = payload.PipelineName
 			m.stageName = payload.StageName
 			m.buildID = payload.ExternalExecutionID
+			m.openLog = msg.trigger
 			m.SetColumns(m.width)
 			m.refresh(m.buildID)
 
+		case eventMsg:
+			if msg.data.(string) == m.buildID && !m.ui.refreshing {
+				m.refresh(m.buildID)
+			}
+
 		case viewUpdate:
 			rows := msg.data.([]table.Row)
 			m.SetColumns(m.width)
 			m.SetRows(rows)
+			if m.openLog {
+				m.openLog = false
+				go m.ui.changeView(codebuildView, logView, PagerSelector{name: m.name})
+			}
 		}
 	}
 
-	*m.Model, _ = m.Model.Update(msg)
-	return m, nil
+	var cmd tea.Cmd
+	*m.Model, cmd = m.Model.Update(msg)
+	return m, cmd
+}
+
+// open opens the log or the buildspec of the selected row
+func (m *CodeBuildTable) open() {
+	s := m.SelectedRow()
+	if strings.Contains(s[0], "URL") {
+		m.ui.changeView(codebuildView, logView, PagerSelector{name: m.name})
+	} else {
+		m.ui.changeView(codebuildView, buildspecView, PagerSelector{name: m.name, content: s[1]})
+	}
 }
 
 // View implement the tea.Model interface
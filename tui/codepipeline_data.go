@@ -3,10 +3,13 @@ package tui
 import (
 	"fmt"
 	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	awsqueries "github.com/fabio42/codeplumber/aws"
 	"github.com/fabio42/codeplumber/models/table"
+	"github.com/fabio42/codeplumber/pipeline"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/codepipeline"
@@ -17,34 +20,82 @@ import (
 
 const (
 	separatorTransition = "--"
-	separatorStage      = "└"
 )
 
 // PipelineResource describe a AWS CodePipeline Resource
 type PipelineResource struct {
 	PipelineName        string
+	PipelineExecutionID string
 	StageName           string
 	ActionName          string
+	Provider            string
+	Category            string
+	Region              string
+	Configuration       map[string]string
 	ExternalExecutionID string
+	Token               string
 	Status              string
 }
 
+// maxPipelineExecutions is the number of recent executions which can be selected in the pipeline view
+const maxPipelineExecutions = 10
+
+// pipelineRows are the rows of the pipeline view and the executions they were built from
+type pipelineRows struct {
+	rows       []table.Row
+	executions []types.PipelineExecutionSummary
+	// actions are the action executions of the selected execution, by stage/action name
+	actions map[string]types.ActionExecutionDetail
+
+	// name, definition and state describe the pipeline, they are merged in the data cache by the view update
+	name       string
+	definition *codepipeline.GetPipelineOutput
+	state      *codepipeline.GetPipelineStateOutput
+}
+
 func (m *PipelineTable) refresh() {
-	go refreshPipelineOps(m.name, m.ui)
+	// The cached pipeline is copied on the Update loop, the data cache is only written by the view update
+	var cached *awsqueries.Pipeline
+	if pipeline, ok := m.ui.dataCache.pipelines[m.name]; ok {
+		cached = &pipeline
+	}
+	go refreshPipelineOps(m.name, m.execution, m.variables, cached, m.ui)
+}
+
+// mergePipeline stores the refreshed definition and state of a pipeline in the data cache
+func (c *uiData) mergePipeline(data pipelineRows) {
+	pipeline, ok := c.dataCache.pipelines[data.name]
+	if !ok || data.definition == nil || data.state == nil {
+		return
+	}
+	pipeline.Data = data.definition
+	pipeline.StateData = data.state
+	c.dataCache.pipelines[data.name] = pipeline
+	config.Recorder.Record("DataCachePipelines.json", c.dataCache.pipelines)
 }
 
-func refreshPipelineOps(name string, c *uiData) {
+// refreshPipelineOps builds the rows of a pipeline, cached is nil when the pipeline is not listed
+// Statuses are those of the latest state of each stage, or those of execution when an execution is selected
+func refreshPipelineOps(name, execution string, variables bool, cached *awsqueries.Pipeline, c *uiData) {
 	var rows []table.Row
 	var err error
 	var stateData *codepipeline.GetPipelineStateOutput
 	var infoData *codepipeline.GetPipelineOutput
+	var executions []types.PipelineExecutionSummary
+	var pipelineExecution *types.PipelineExecution
+	var actionDetails []types.ActionExecutionDetail
+	// observed is the state of the pipeline, when its last execution is known
+	var observed []awsqueries.Pipeline
 
 	c.startSpinner()
 
 	if config.Mode.Replay {
-		stateData = c.dataCache.pipelines[name].StateData
-		infoData = c.dataCache.pipelines[name].Data
-	} else if pipeline, ok := c.dataCache.pipelines[name]; ok {
+		if cached != nil {
+			stateData = cached.StateData
+			infoData = cached.Data
+		}
+	} else if cached != nil {
+		pipeline := *cached
 		stateData, err = awsqueries.GetPipelineState(config.AwsConfig, name)
 		if err != nil {
 			log.Fatal().Msgf("error: %v", err)
@@ -58,36 +109,82 @@ func refreshPipelineOps(name string, c *uiData) {
 		pipeline.StateData = stateData
 		pipeline.Data = infoData
 
-		c.dataCache.pipelines[name] = pipeline
-		config.Recorder.Record("DataCachePipelines.json", c.dataCache.pipelines)
+		// Variables are resolved per execution, those of the most recent execution are displayed by default
+		executions, err = awsqueries.ListPipelineExecutions(config.AwsConfig, name, maxPipelineExecutions)
+		if err != nil {
+			log.Debug().Str("model", "tui").Str("func", "refreshPipelineOps").Msgf("failed to list executions: %v", err)
+		}
+		if len(executions) > 0 {
+			pipeline.LastExecutionID = aws.ToString(executions[0].PipelineExecutionId)
+			pipeline.LastExecutionStatus = string(executions[0].Status)
+			observed = append(observed, pipeline)
+		}
+		variablesExecution := execution
+		if variablesExecution == "" && len(executions) > 0 {
+			variablesExecution = aws.ToString(executions[0].PipelineExecutionId)
+		}
+		if variablesExecution != "" {
+			pipelineExecution, err = awsqueries.GetPipelineExecution(config.AwsConfig, name, variablesExecution)
+			if err != nil {
+				log.Debug().Str("model", "tui").Str("func", "refreshPipelineOps").Msgf("failed to get execution %v: %v", variablesExecution, err)
+			}
+			if execution != "" || variables {
+				actionDetails, err = awsqueries.ListActionExecutions(config.AwsConfig, name, variablesExecution)
+				if err != nil {
+					log.Debug().Str("model", "tui").Str("func", "refreshPipelineOps").Msgf("failed to list action executions of %v: %v", variablesExecution, err)
+				}
+			}
+		}
 	}
 
-	for stageKey, stage := range c.dataCache.pipelines[name].Data.Pipeline.Stages {
-		stageState := stateData.StageStates[stageKey]
-		if len(rows) > 0 {
-			var transitionState string
+	// Action executions are listed from the most recent to the oldest, only the latest attempt of each action is kept
+	actions := make(map[string]types.ActionExecutionDetail)
+	for _, detail := range actionDetails {
+		key := actionKey(aws.ToString(detail.StageName), aws.ToString(detail.ActionName))
+		if _, ok := actions[key]; !ok {
+			actions[key] = detail
+		}
+	}
 
-			if stageState.InboundTransitionState.Enabled {
+	if infoData == nil || infoData.Pipeline == nil || stateData == nil {
+		c.stopSpinner()
+		c.errorMsg(pipelineView, fmt.Sprintf("CodePipeline %v is not described", name))
+		return
+	}
+	declaration := infoData.Pipeline
+	rows = append(rows, pipelineHeaderRows(declaration, execution, executions, pipelineExecution)...)
+
+	for stageIdx, stage := range declaration.Stages {
+		// States are matched by name, new stages and actions have no state until the next execution
+		var stageState types.StageState
+		if idx := findStageByName(stateData.StageStates, aws.ToString(stage.Name)); idx != -1 {
+			stageState = stateData.StageStates[idx]
+		}
+		if stageIdx > 0 {
+			transitionState := "Disabled"
+			if stageState.InboundTransitionState == nil || stageState.InboundTransitionState.Enabled {
 				transitionState = "Enabled"
-			} else {
-				transitionState = "Disabled"
 			}
 
-			rows = append(rows, table.Row{"", "", "", "", ""})
+			rows = append(rows, table.Row{"", "", "", "", "", ""})
 			rows = append(rows, table.Row{
 				separatorTransition + " Transition",
 				"Transition",
 				*stage.Name,
 				transitionState,
 				"",
+				"",
 			})
-			rows = append(rows, table.Row{"", "", "", "", ""})
 		}
+		rows = append(rows, table.Row{"", "", "", "", "", ""})
 
 		var execStatus string
-		if stageState.LatestExecution == nil {
+		switch {
+		case execution != "" && (stageState.LatestExecution == nil || aws.ToString(stageState.LatestExecution.PipelineExecutionId) != execution):
+			execStatus = stageExecutionStatus(stage, actions)
+		case stageState.LatestExecution == nil:
 			execStatus = "N/A"
-		} else {
+		default:
 			execStatus = string(stageState.LatestExecution.Status)
 		}
 		rows = append(rows, table.Row{
@@ -96,74 +193,314 @@ func refreshPipelineOps(name string, c *uiData) {
 			"",
 			execStatus,
 			"",
+			"",
 		})
 
-		for actionKey, action := range stage.Actions {
-			var actionStatus string
-			var actionLastUpdateTime string
-
-			actions := stageState.ActionStates[actionKey]
+		// Actions sharing a run order run in parallel and are displayed as branches
+		for _, group := range pipeline.RunOrderGroups(stage.Actions) {
+			for idx, action := range group {
+				var actionStatus string
+				var actionLastUpdateTime string
+
+				var status types.ActionExecutionStatus
+				var lastStatusChange *time.Time
+				detail, selected := actions[actionKey(aws.ToString(stage.Name), aws.ToString(action.Name))]
+				if execution != "" {
+					if selected {
+						status = detail.Status
+						lastStatusChange = detail.LastUpdateTime
+					}
+				} else if actionIdx := findActionByName(stageState.ActionStates, aws.ToString(action.Name)); actionIdx != -1 && stageState.ActionStates[actionIdx].LatestExecution != nil {
+					status = stageState.ActionStates[actionIdx].LatestExecution.Status
+					lastStatusChange = stageState.ActionStates[actionIdx].LatestExecution.LastStatusChange
+				}
 
-			switch action.ActionTypeId.Category {
-			case "Approval":
-				actionStatus = string(actions.LatestExecution.Status)
-				if actionStatus == "InProgress" && actions.LatestExecution.LastStatusChange == nil {
+				switch {
+				case action.ActionTypeId.Category == types.ActionCategoryApproval &&
+					status == types.ActionExecutionStatusInProgress && lastStatusChange == nil:
 					actionStatus = "Pending"
 					actionLastUpdateTime = "N/A"
-				} else {
-					actionLastUpdateTime = PrintTime(actions.LatestExecution.LastStatusChange)
-				}
-
-			default:
-				if actions.LatestExecution == nil || actions.LatestExecution.LastStatusChange == nil {
+				case status == "" || lastStatusChange == nil:
 					actionStatus = "Waiting"
 					actionLastUpdateTime = "..."
-				} else {
-					log.Debug().Str("model", "tui").Str("func", "refreshPipelineOps").Msgf("action: %v", actions)
-					actionStatus = string(actions.LatestExecution.Status)
-					actionLastUpdateTime = PrintTime(actions.LatestExecution.LastStatusChange)
+				default:
+					log.Debug().Str("model", "tui").Str("func", "refreshPipelineOps").Msgf("action: %v, status: %v", aws.ToString(action.Name), status)
+					actionStatus = string(status)
+					actionLastUpdateTime = PrintTime(lastStatusChange)
+				}
+
+				order := ""
+				if idx == 0 {
+					order = fmt.Sprint(pipeline.RunOrder(action))
+				}
+				actionName := *action.Name
+				actionType := aws.ToString(action.ActionTypeId.Provider)
+				actionCategory := string(action.ActionTypeId.Category)
+
+				rows = append(rows, table.Row{
+					fmt.Sprintf("%3v %v %v", order, runOrderMarker(idx, len(group)), actionName),
+					fmt.Sprintf("%v/%v", actionType, actionCategory),
+					*stage.Name,
+					actionStatus,
+					actionLastUpdateTime,
+					actionName,
+				})
+
+				if variables && selected && detail.Output != nil {
+					rows = append(rows, actionVariablesRows(action, detail.Output.OutputVariables)...)
 				}
 			}
+		}
+	}
+	c.stopSpinner()
+	c.updateView(pipelineView, pipelineRows{
+		rows:       rows,
+		executions: executions,
+		actions:    actions,
+		name:       name,
+		definition: infoData,
+		state:      stateData,
+	})
+	c.observe(observed...)
+}
 
-			actionName := *action.Name
-			actionType := aws.ToString(action.ActionTypeId.Provider)
-			actionCategory := string(action.ActionTypeId.Category)
+func actionKey(stageName, actionName string) string {
+	return stageName + "/" + actionName
+}
 
-			rows = append(rows, table.Row{
-				fmt.Sprintf("%s %v", separatorStage, actionName),
-				fmt.Sprintf("%v/%v", actionType, actionCategory),
-				*stage.Name,
-				actionStatus,
-				actionLastUpdateTime,
-			})
+// stageExecutionStatus returns the status of a stage in a past execution from the status of its actions
+func stageExecutionStatus(stage types.StageDeclaration, actions map[string]types.ActionExecutionDetail) string {
+	found, succeeded := 0, 0
+	status := "N/A"
+	for _, action := range stage.Actions {
+		detail, ok := actions[actionKey(aws.ToString(stage.Name), aws.ToString(action.Name))]
+		if !ok {
+			continue
+		}
+		found++
+		switch detail.Status {
+		case types.ActionExecutionStatusFailed:
+			return string(types.StageExecutionStatusFailed)
+		case types.ActionExecutionStatusInProgress:
+			status = string(types.StageExecutionStatusInProgress)
+		case types.ActionExecutionStatusAbandoned:
+			status = string(types.StageExecutionStatusStopped)
+		case types.ActionExecutionStatusSucceeded:
+			succeeded++
 		}
 	}
-	c.stopSpinner()
-	c.updateView(pipelineView, rows)
+	if found > 0 && succeeded == len(stage.Actions) {
+		return string(types.StageExecutionStatusSucceeded)
+	}
+	return status
 }
 
-// Return PipelineData for the given pipeline name
-func (m *PipelineTable) getCodebuildResource(pipelineName, stageName, stageSection, actionName string) (PipelineResource, error) {
-	var pipelineData PipelineResource
-	var stateData *codepipeline.GetPipelineStateOutput
-	var err error
+// pipelineHeaderRows returns the rows describing the pipeline: type, execution mode, selected execution, triggers and variables
+func pipelineHeaderRows(declaration *types.PipelineDeclaration, execution string, executions []types.PipelineExecutionSummary, pipelineExecution *types.PipelineExecution) []table.Row {
+	pipelineType := string(declaration.PipelineType)
+	if pipelineType == "" {
+		pipelineType = string(types.PipelineTypeV1)
+	}
+	executionMode := string(declaration.ExecutionMode)
+	if executionMode == "" {
+		executionMode = string(types.ExecutionModeSuperseded)
+	}
+	rows := []table.Row{
+		{"Pipeline type", pipelineType, "", "", "", ""},
+		{"Execution mode", executionMode, "", "", "", ""},
+	}
 
-	pipeline := m.ui.dataCache.pipelines[pipelineName]
-	stateData = pipeline.StateData
-	stageIdx := findStageByName(stateData.StageStates, stageName)
-	actionIdx := findActionByName(stateData.StageStates[stageIdx].ActionStates, actionName)
+	inProgress := 0
+	for _, summary := range executions {
+		if summary.Status == types.PipelineExecutionStatusInProgress {
+			inProgress++
+		}
+	}
+	if execution == "" {
+		rows = append(rows, table.Row{"Execution", fmt.Sprintf("Latest state, %d in progress", inProgress), "", "", "", ""})
+	} else {
+		for idx, summary := range executions {
+			if aws.ToString(summary.PipelineExecutionId) == execution {
+				rows = append(rows, table.Row{
+					"Execution",
+					fmt.Sprintf("%v (%d/%d)", execution, idx+1, len(executions)),
+					"",
+					string(summary.Status),
+					PrintTime(summary.LastUpdateTime),
+					"",
+				})
+			}
+		}
+	}
 
+	for _, trigger := range declaration.Triggers {
+		if trigger.GitConfiguration == nil {
+			continue
+		}
+		label := "Trigger " + aws.ToString(trigger.GitConfiguration.SourceActionName)
+		for _, push := range trigger.GitConfiguration.Push {
+			rows = append(rows, table.Row{label, "push " + gitFilter(push.Branches, push.FilePaths, push.Tags), "", "", "", ""})
+		}
+		for _, pr := range trigger.GitConfiguration.PullRequest {
+			events := make([]string, len(pr.Events))
+			for i, event := range pr.Events {
+				events[i] = strings.ToLower(string(event))
+			}
+			if len(events) == 0 {
+				events = []string{"all"}
+			}
+			rows = append(rows, table.Row{label, fmt.Sprintf("pull request %v %v", strings.Join(events, ","), gitFilter(pr.Branches, pr.FilePaths, nil)), "", "", "", ""})
+		}
+	}
+
+	// Resolved variables of the execution, the default values when the execution did not resolve them
+	resolved := make(map[string]string)
+	if pipelineExecution != nil {
+		for _, variable := range pipelineExecution.Variables {
+			resolved[aws.ToString(variable.Name)] = aws.ToString(variable.ResolvedValue)
+		}
+	}
+	for _, variable := range declaration.Variables {
+		name := aws.ToString(variable.Name)
+		value, ok := resolved[name]
+		if !ok {
+			value = aws.ToString(variable.DefaultValue) + " (default)"
+		}
+		rows = append(rows, table.Row{fmt.Sprintf("#{variables.%v}", name), value, "", "", "", ""})
+	}
+	return rows
+}
+
+// gitFilter describes the filters of a Git trigger: "branches: main,release/* !wip, paths: src/**"
+func gitFilter(branches *types.GitBranchFilterCriteria, paths *types.GitFilePathFilterCriteria, tags *types.GitTagFilterCriteria) string {
+	var filters []string
+	criteria := func(kind string, includes, excludes []string) {
+		if len(includes) == 0 && len(excludes) == 0 {
+			return
+		}
+		filter := kind + ": " + strings.Join(includes, ",")
+		if len(excludes) > 0 {
+			filter += " !" + strings.Join(excludes, ",!")
+		}
+		filters = append(filters, strings.TrimSpace(filter))
+	}
+	if branches != nil {
+		criteria("branches", branches.Includes, branches.Excludes)
+	}
+	if paths != nil {
+		criteria("paths", paths.Includes, paths.Excludes)
+	}
+	if tags != nil {
+		criteria("tags", tags.Includes, tags.Excludes)
+	}
+	if len(filters) == 0 {
+		return "all"
+	}
+	return strings.Join(filters, ", ")
+}
+
+// actionVariablesRows returns the output variables of an action, referenced by their namespace when the action has one
+func actionVariablesRows(action types.ActionDeclaration, variables map[string]string) []table.Row {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([]table.Row, len(names))
+	for i, name := range names {
+		reference := aws.ToString(action.Name) + "." + name
+		if action.Namespace != nil {
+			reference = fmt.Sprintf("#{%v.%v}", aws.ToString(action.Namespace), name)
+		}
+		rows[i] = table.Row{"        ⤷ " + reference, variables[name], "", "", "", ""}
+	}
+	return rows
+}
+
+// getActionResource returns the PipelineResource of an action for the given pipeline
+func (c *uiData) getActionResource(pipelineName, stageName, actionName string) (PipelineResource, error) {
+	var pipelineData PipelineResource
+
+	pipeline := c.dataCache.pipelines[pipelineName]
 	pipelineData.PipelineName = pipelineName
-	pipelineData.StageName = stageSection
+	pipelineData.StageName = stageName
 	pipelineData.ActionName = actionName
-	pipelineData.Status = string(stateData.StageStates[stageIdx].ActionStates[actionIdx].LatestExecution.Status)
 
-	for stateData.StageStates[stageIdx].ActionStates[actionIdx].LatestExecution.ExternalExecutionId == nil {
+	// The definition and the state are only described once the pipeline view has been refreshed
+	if pipeline.Data == nil || pipeline.Data.Pipeline == nil || pipeline.StateData == nil {
 		return pipelineData, fmt.Errorf("pipelineDataNotReady")
 	}
-	pipelineData.ExternalExecutionID = *stateData.StageStates[stageIdx].ActionStates[actionIdx].LatestExecution.ExternalExecutionId
 
-	return pipelineData, err
+	for _, stage := range pipeline.Data.Pipeline.Stages {
+		if aws.ToString(stage.Name) != stageName {
+			continue
+		}
+		for _, action := range stage.Actions {
+			if aws.ToString(action.Name) == actionName {
+				pipelineData.Provider = aws.ToString(action.ActionTypeId.Provider)
+				pipelineData.Category = string(action.ActionTypeId.Category)
+				pipelineData.Region = aws.ToString(action.Region)
+				pipelineData.Configuration = action.Configuration
+			}
+		}
+	}
+
+	stateData := pipeline.StateData
+	stageIdx := findStageByName(stateData.StageStates, stageName)
+	if stageIdx == -1 {
+		return pipelineData, fmt.Errorf("pipelineDataNotReady")
+	}
+	stageState := stateData.StageStates[stageIdx]
+	if stageState.LatestExecution != nil {
+		pipelineData.PipelineExecutionID = aws.ToString(stageState.LatestExecution.PipelineExecutionId)
+	}
+
+	actionIdx := findActionByName(stageState.ActionStates, actionName)
+	if actionIdx == -1 || stageState.ActionStates[actionIdx].LatestExecution == nil {
+		return pipelineData, fmt.Errorf("pipelineDataNotReady")
+	}
+	execution := stageState.ActionStates[actionIdx].LatestExecution
+	pipelineData.Status = string(execution.Status)
+	pipelineData.ExternalExecutionID = aws.ToString(execution.ExternalExecutionId)
+	pipelineData.Token = aws.ToString(execution.Token)
+
+	return pipelineData, nil
+}
+
+// getApprovalResource returns the manual approval action following the given action
+// If the action is itself a manual approval, it is returned as is
+func (c *uiData) getApprovalResource(resource PipelineResource) (PipelineResource, error) {
+	if resource.Category == string(types.ActionCategoryApproval) {
+		return resource, nil
+	}
+
+	data := c.dataCache.pipelines[resource.PipelineName].Data
+	if data == nil || data.Pipeline == nil {
+		return resource, fmt.Errorf("pipelineDataNotReady")
+	}
+	found := false
+	for _, stage := range data.Pipeline.Stages {
+		for _, group := range pipeline.RunOrderGroups(stage.Actions) {
+			for _, action := range group {
+				if found && action.ActionTypeId.Category == types.ActionCategoryApproval {
+					return c.getActionResource(resource.PipelineName, aws.ToString(stage.Name), aws.ToString(action.Name))
+				}
+				if aws.ToString(stage.Name) == resource.StageName && aws.ToString(action.Name) == resource.ActionName {
+					found = true
+				}
+			}
+		}
+	}
+	return PipelineResource{}, fmt.Errorf("no manual approval found after %v", resource.ActionName)
+}
+
+// approve approves or rejects a manual approval action waiting for a response
+func (c *uiData) approve(resource PipelineResource, status types.ApprovalStatus, summary string) error {
+	if resource.Status != string(types.ActionExecutionStatusInProgress) || resource.Token == "" {
+		return fmt.Errorf("%v is not waiting for approval", resource.ActionName)
+	}
+	return awsqueries.PutApprovalResult(config.AwsConfig, resource.PipelineName, resource.StageName, resource.ActionName, resource.Token, status, summary)
 }
 
 func findStageByName(stages []types.StageState, name string) int {
@@ -188,8 +525,23 @@ func (m *PipelineTable) start() {
 	awsqueries.StartPipelineExecution(config.AwsConfig, m.name)
 }
 
+// stop stops the latest execution of the pipeline, the actions in progress complete
+func (m *PipelineTable) stop() error {
+	execution := m.ui.dataCache.pipelines[m.name].LastExecutionID
+	if len(m.executions) > 0 {
+		execution = aws.ToString(m.executions[0].PipelineExecutionId)
+	}
+	if execution == "" {
+		return fmt.Errorf("no execution of %v to stop", m.name)
+	}
+	return awsqueries.StopPipelineExecution(config.AwsConfig, m.name, execution, "Stopped from codeplumber")
+}
+
 func (m *PipelineTable) restartStage(pipeline PipelineResource) {
-	pipelineExcutionID := m.ui.dataCache.pipelines[pipeline.PipelineName].LastExecutionID
+	pipelineExcutionID := pipeline.PipelineExecutionID
+	if pipelineExcutionID == "" {
+		pipelineExcutionID = m.ui.dataCache.pipelines[pipeline.PipelineName].LastExecutionID
+	}
 	err := awsqueries.RetryPipelineStage(config.AwsConfig, pipelineExcutionID, pipeline.PipelineName, pipeline.StageName)
 	if err != nil {
 		log.Debug().Str("model", "tui").Str("func", "PipelineTable.restartStage").Msgf("Error starting pipeline: %v", err)
@@ -207,7 +559,7 @@ func (m *PipelineTable) toggleTransition(action string, reason string) error {
 		for true {
 			inProgress := false
 			time.Sleep(2 * time.Second)
-			go refreshPipelineOps(m.name, m.ui)
+			m.refresh()
 			for _, r := range m.Rows() {
 				if slices.Contains(r, "InProgress") {
 					inProgress = true
@@ -226,5 +578,5 @@ func (m *PipelineTable) toggleTransition(action string, reason string) error {
 }
 
 func (m *PipelineTable) browse() {
-	browser.OpenURL(fmt.Sprintf("https://%s.console.aws.amazon.com/codesuite/codepipeline/pipelines/%v/view", config.AwsConfig.Region, m.name))
+	browser.OpenURL(awsqueries.ConsoleURL(config.AwsConfig.Region, fmt.Sprintf("/codesuite/codepipeline/pipelines/%v/view?region=%v", m.name, config.AwsConfig.Region)))
 }
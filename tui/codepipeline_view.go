@@ -6,6 +6,8 @@ import (
 
 	"github.com/fabio42/codeplumber/models/table"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline/types"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -14,9 +16,13 @@ import (
 
 const (
 	pipelineStart     = "codepipelineStart"
+	pipelineStop      = "codepipelineStop"
 	stageRestart      = "codepipelineStageRestart"
 	transitionEnable  = "codepipelineTransitionEnable"
 	transitionDisable = "codepipelineTransitionDisable"
+	approvalApprove   = "codepipelineApprovalApprove"
+	approvalReject    = "codepipelineApprovalReject"
+	pipelineDiff      = "codepipelineDiff"
 )
 
 // PipelineTable represent a AWS CodePipeline details
@@ -25,16 +31,23 @@ type PipelineTable struct {
 	name          string
 	width, height int
 	ui            *uiData
-	help          help.Model
-	confirm       struct {
+	// execution is the selected execution, the latest state of the stages is displayed when empty
+	execution  string
+	executions []types.PipelineExecutionSummary
+	actions    map[string]types.ActionExecutionDetail
+	variables  bool
+	help       help.Model
+	confirm    struct {
 		wait    bool
 		element interface{}
 	}
+	// target is the command selected in the command palette, run once the pipeline is loaded
+	target *paletteItem
 }
 
 // NewPipelineTable returns a new PipelineTable
 func NewPipelineTable(ui *uiData) *PipelineTable {
-	t := table.New()
+	t := table.New(table.WithKeyMap(tableKeys))
 	t.SetStyles(ui.getTablePatchedStyle())
 	return &PipelineTable{
 		Model: &t,
@@ -45,7 +58,7 @@ func NewPipelineTable(ui *uiData) *PipelineTable {
 
 // SetColumns set the columns of the table
 func (m *PipelineTable) SetColumns(width int) {
-	cols := make([]table.Column, 5)
+	cols := make([]table.Column, 6)
 
 	width = width - 5
 	typeSize := percent(width, 20, 40)
@@ -60,6 +73,8 @@ func (m *PipelineTable) SetColumns(width int) {
 	cols[2] = table.Column{Title: "Stage Name", Width: stageSize}
 	cols[3] = table.Column{Title: "Status", Width: statusSize}
 	cols[4] = table.Column{Title: "Last execution", Width: lastExecutionSize}
+	// Hidden, identifies the action of a row
+	cols[5] = table.Column{Title: "Action Name", Width: 0}
 	m.Model.SetColumns(cols)
 	m.Focus()
 }
@@ -108,7 +123,7 @@ func (m *PipelineTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, codePipelineKeys.ReStart):
 			s := m.SelectedRow()
-			if strings.HasPrefix(s[0], separatorStage) {
+			if isActionRow(s) {
 				_, p, _ := m.selectComponent(s)
 				if p.Status == "Failed" {
 					m.ui.confirm(stageRestart, "Restart CodePipeline Stage?", p)
@@ -127,19 +142,62 @@ func (m *PipelineTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-		case key.Matches(msg, allKeys.Select):
+		case key.Matches(msg, codePipelineKeys.ChangeSet):
 			s := m.SelectedRow()
-			if strings.HasPrefix(s[0], separatorStage) {
-				stageType, d, err := m.selectComponent(s)
-				log.Debug().Str("model", "tui").Str("func", "PipelineTable.Update").Msgf("selected stageType: %v, data: %v", stageType, d)
+			if isActionRow(s) {
+				_, p, err := m.selectComponent(s)
+				switch {
+				case err != nil:
+					go m.ui.errorMsg(pipelineView, "Execution not ready... refreshing.")
+					m.refresh()
+				case p.Provider != "CloudFormation" || p.Configuration["ChangeSetName"] == "":
+					go m.ui.errorMsg(pipelineView, "Change sets are only available on CloudFormation change set actions.")
+				default:
+					m.ui.changeView(pipelineView, changesetView, p)
+				}
+			}
+
+		case key.Matches(msg, codePipelineKeys.Artifacts):
+			s := m.SelectedRow()
+			if isActionRow(s) {
+				_, p, err := m.selectComponent(s)
 				if err != nil {
 					go m.ui.errorMsg(pipelineView, "Execution not ready... refreshing.")
 					m.refresh()
 				} else {
-					m.ui.changeView(pipelineView, stageType, d)
+					m.ui.changeView(pipelineView, artifactsView, p)
 				}
 			}
 
+		case key.Matches(msg, codePipelineKeys.Definition):
+			if m.ui.dataCache.pipelines[m.name].Data == nil {
+				go m.ui.errorMsg(pipelineView, "Definition not ready... refreshing.")
+				m.refresh()
+			} else {
+				m.ui.changeView(pipelineView, definitionView, PagerSelector{name: m.name})
+			}
+
+		case key.Matches(msg, codePipelineKeys.Execution):
+			m.execution = m.nextExecution()
+			m.refresh()
+
+		case key.Matches(msg, codePipelineKeys.Variables):
+			m.variables = !m.variables
+			m.refresh()
+
+		case key.Matches(msg, codePipelineKeys.Diff):
+			m.ui.requestInput(pipelineDiff, "text", "DIFF with NAME, NAME:VERSION or file (empty to cancel):", nil)
+
+		case key.Matches(msg, codePipelineKeys.Approve), key.Matches(msg, codePipelineKeys.Reject):
+			if s := m.SelectedRow(); isActionRow(s) {
+				m.reviewApproval(s, key.Matches(msg, codePipelineKeys.Approve))
+			}
+
+		case key.Matches(msg, allKeys.Select):
+			if s := m.SelectedRow(); isActionRow(s) {
+				m.selectAction(s, false)
+			}
+
 		case key.Matches(msg, allKeys.Browse):
 			m.browse()
 		}
@@ -150,16 +208,35 @@ func (m *PipelineTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.class {
 		case viewChange:
 			m.name = msg.data.(string)
+			m.execution = ""
+			m.executions = nil
 			m.SetColumns(m.width)
 			m.SetRows([]table.Row{})
 			m.refresh()
 
 		case viewUpdate:
-			rows := msg.data.([]table.Row)
+			data := msg.data.(pipelineRows)
+			m.ui.mergePipeline(data)
+			m.executions = data.executions
+			m.actions = data.actions
 			m.SetColumns(m.width)
-			m.SetRows(rows)
+			m.SetRows(data.rows)
+			if m.target != nil {
+				m.runTarget()
+			}
+
+		case eventMsg:
+			if msg.data.(string) == m.name && !m.ui.refreshing {
+				m.refresh()
+			}
 
 		case response:
+			if msg.src == pipelineDiff {
+				if msg.trigger {
+					go m.ui.diffPipeline(m.name, msg.data.(string))
+				}
+				break
+			}
 			if msg.trigger {
 				switch msg.src {
 				case transitionDisable:
@@ -179,20 +256,32 @@ func (m *PipelineTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if msg.trigger {
 						m.start()
 					}
+				case pipelineStop:
+					err = m.stop()
+				case approvalApprove:
+					err = m.ui.approve(msg.reference.(PipelineResource), types.ApprovalStatusApproved, "Approved from codeplumber")
+				case approvalReject:
+					err = m.ui.approve(msg.reference.(PipelineResource), types.ApprovalStatusRejected, msg.data.(string))
 				}
 			}
 			if err != nil {
-				m.ui.errorMsg(pipelineView, err.Error())
+				go m.ui.errorMsg(pipelineView, err.Error())
 			}
 			m.refresh()
 		}
 
 	case refresh:
 		m.refresh()
+
+	case table.SelectMsg:
+		if s := m.SelectedRow(); isActionRow(s) {
+			m.selectAction(s, false)
+		}
 	}
 
-	*m.Model, _ = m.Model.Update(msg)
-	return m, tea.Batch(cmds...)
+	var cmd tea.Cmd
+	*m.Model, cmd = m.Model.Update(msg)
+	return m, tea.Batch(append(cmds, cmd)...)
 }
 
 // View implement the tea.Model interface
@@ -208,29 +297,151 @@ func (m *PipelineTable) View() string {
 }
 
 func (m *PipelineTable) selectComponent(row table.Row) (string, PipelineResource, error) {
-	var d PipelineResource
-	var err error
-
-	actionName := strings.Split(row[0], " ")[1]
+	actionName := row[5]
 	stageName := row[2]
-	k := row[1]
-	stageType := strings.ToLower(strings.Split(k, "/")[0])
-	stageSection := strings.Split(k, "/")[1]
-
-	switch stageType {
-	case codebuildView:
-		d, err = m.getCodebuildResource(m.name, stageName, stageSection, actionName)
-		if err != nil {
-			return "", d, err
+
+	d, err := m.ui.getActionResource(m.name, stageName, actionName)
+	if err != nil {
+		return "", d, err
+	}
+
+	// The state only holds the latest execution of each action, a past or parallel execution may be selected
+	if m.execution != "" && d.PipelineExecutionID != m.execution {
+		detail, ok := m.actions[actionKey(stageName, actionName)]
+		if !ok {
+			return "", d, fmt.Errorf("pipelineDataNotReady")
 		}
+		d.PipelineExecutionID = m.execution
+		d.Status = string(detail.Status)
+		d.Token = ""
+		d.ExternalExecutionID = ""
+		if detail.Output != nil && detail.Output.ExecutionResult != nil {
+			d.ExternalExecutionID = aws.ToString(detail.Output.ExecutionResult.ExternalExecutionId)
+		}
+	}
+
+	view := providerView(d.Provider, d.Category)
+	if view == codebuildView && d.ExternalExecutionID == "" {
+		return "", d, fmt.Errorf("pipelineDataNotReady")
+	}
+	return view, d, nil
+}
+
+// nextExecution returns the execution following the selected one, back to the latest state after the oldest execution
+func (m *PipelineTable) nextExecution() string {
+	if m.execution == "" {
+		if len(m.executions) == 0 {
+			return ""
+		}
+		return aws.ToString(m.executions[0].PipelineExecutionId)
+	}
+	for idx, summary := range m.executions {
+		if aws.ToString(summary.PipelineExecutionId) == m.execution && idx+1 < len(m.executions) {
+			return aws.ToString(m.executions[idx+1].PipelineExecutionId)
+		}
+	}
+	return ""
+}
+
+// isActionRow returns true if the row is an action of a stage
+// selectAction opens the view of the action of a row, logs opens the log of a CodeBuild action once it is loaded
+func (m *PipelineTable) selectAction(row table.Row, logs bool) {
+	stageType, d, err := m.selectComponent(row)
+	log.Debug().Str("model", "tui").Str("func", "PipelineTable.selectAction").Msgf("selected stageType: %v, data: %v", stageType, d)
+	switch {
+	case err != nil:
+		go m.ui.errorMsg(pipelineView, "Execution not ready... refreshing.")
+		m.refresh()
+	case logs && stageType != codebuildView:
+		go m.ui.errorMsg(pipelineView, "Logs are only available on CodeBuild actions.")
+	case logs:
+		go m.ui.changeViewWithTrigger(pipelineView, stageType, d)
 	default:
-		d = PipelineResource{
-			PipelineName: m.name,
-			StageName:    actionName,
-			ActionName:   stageSection,
+		go m.ui.changeView(pipelineView, stageType, d)
+	}
+}
+
+// reviewApproval approves or rejects the approval action of a row
+func (m *PipelineTable) reviewApproval(row table.Row, approve bool) {
+	_, p, err := m.selectComponent(row)
+	if err == nil {
+		p, err = m.ui.getApprovalResource(p)
+	}
+	switch {
+	case err != nil:
+		go m.ui.errorMsg(pipelineView, err.Error())
+	case approve:
+		go m.ui.confirm(approvalApprove, fmt.Sprintf("Approve %v?", p.ActionName), p)
+	default:
+		go m.ui.requestInput(approvalReject, "reason", fmt.Sprintf("REJECT %v: reason (empty to cancel):", p.ActionName), p)
+	}
+}
+
+// runTarget runs the command selected in the command palette on the pipeline or on one of its actions
+func (m *PipelineTable) runTarget() {
+	target := *m.target
+	m.target = nil
+	log.Debug().Str("model", "tui").Str("func", "PipelineTable.runTarget").Msgf("target: %v", target.label)
+
+	switch target.command {
+	case paletteStart:
+		go m.ui.confirm(pipelineStart, "Start this CodePipeline?", nil)
+		return
+	case paletteStop:
+		go m.ui.confirm(pipelineStop, "Stop the execution in progress of this CodePipeline?", nil)
+		return
+	case paletteDefinition:
+		go m.ui.changeView(pipelineView, definitionView, PagerSelector{name: m.name})
+		return
+	}
+	if target.action == "" {
+		return
+	}
+
+	for i, row := range m.Rows() {
+		if !isActionRow(row) || row[2] != target.stage || row[5] != target.action {
+			continue
+		}
+		m.SetCursor(i)
+		switch target.command {
+		case paletteApprove, paletteReject:
+			m.reviewApproval(row, target.command == paletteApprove)
+		default:
+			m.selectAction(row, target.command == paletteLogs)
+		}
+		return
+	}
+	go m.ui.errorMsg(pipelineView, fmt.Sprintf("Action %v > %v not found in %v.", target.stage, target.action, m.name))
+}
+
+func isActionRow(row table.Row) bool {
+	return len(row) > 5 && row[5] != ""
+}
+
+// providerView returns the detail view supporting an action provider
+// Actions without a dedicated view fall back to the generic action view
+func providerView(provider, category string) string {
+	if category == string(types.ActionCategorySource) {
+		return sourceView
+	}
+
+	switch provider {
+	case "CodeBuild":
+		return codebuildView
+	case "CloudFormation":
+		return cloudformationView
+	case "ECS":
+		return ecsView
+	case "CodeDeploy", "CodeDeployToECS":
+		return codedeployView
+	case "Lambda":
+		return lambdaView
+	case "S3":
+		if category == "Deploy" {
+			return s3View
 		}
 	}
-	return stageType, d, nil
+	return actionView
 }
 
 func (m *PipelineTable) helpView() string {
@@ -240,6 +451,7 @@ func (m *PipelineTable) helpView() string {
 		codePipelineKeys.Start,
 		codePipelineKeys.ReStart,
 		codePipelineKeys.ToggleTransition,
+		codePipelineKeys.Approve,
 		allKeys.Help,
 	})
 }
@@ -258,6 +470,20 @@ func (m *PipelineTable) helpViewFull() string {
 			codePipelineKeys.ReStart,
 			codePipelineKeys.ToggleTransition,
 		},
+		{
+			codePipelineKeys.Approve,
+			codePipelineKeys.Reject,
+			codePipelineKeys.ChangeSet,
+			codePipelineKeys.Artifacts,
+			codePipelineKeys.Definition,
+			codePipelineKeys.Diff,
+		},
+		{
+			codePipelineKeys.Execution,
+			codePipelineKeys.Variables,
+			allKeys.NextPane,
+			allKeys.PrevPane,
+		},
 		{
 			allKeys.Refresh,
 			allKeys.Quit,
@@ -13,74 +13,174 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// refresh lists the pipelines, unless a listing is already running
 func (p *PipelinesTable) refresh() {
-	go pipelinesTableRefresh(p.ui)
+	if p.ui.listing {
+		return
+	}
+	p.ui.listing = true
+	go pipelinesTableRefresh(p.ui, false)
+}
+
+// revalidate refreshes the listing in the background, the displayed rows remain usable
+func (p *PipelinesTable) revalidate() {
+	if p.ui.listing {
+		return
+	}
+	p.ui.listing = true
+	go pipelinesTableRefresh(p.ui, true)
+}
+
+// pipelinesRows are the rows of the pipelines listing
+// Stale rows come from the on-disk cache and are displayed until the listing is refreshed
+type pipelinesRows struct {
+	rows  []table.Row
+	stale bool
+	// pipelines is the listing the rows were built from, it replaces the data cache on the Update loop
+	pipelines map[string]awsqueries.Pipeline
+}
+
+// mergePipelines replaces the listing of the data cache
+// The definition and the state described by the pipeline view are not part of the listing and are kept
+func (c *uiData) mergePipelines(listing map[string]awsqueries.Pipeline) {
+	pipelines := make(map[string]awsqueries.Pipeline, len(listing))
+	for name, pipeline := range listing {
+		if cached, ok := c.dataCache.pipelines[name]; ok {
+			if pipeline.Data == nil {
+				pipeline.Data = cached.Data
+			}
+			if pipeline.StateData == nil {
+				pipeline.StateData = cached.StateData
+			}
+		}
+		pipelines[name] = pipeline
+	}
+	c.dataCache.pipelines = pipelines
 }
 
-func pipelinesTableRefresh(c *uiData) {
-	c.startSpinner()
+func pipelinesTableRefresh(c *uiData, background bool) {
 	var err error
 
+	// On startup cached pipelines are displayed instantly while the listing is refreshed in the background
+	var cached map[string]awsqueries.Pipeline
+	if !c.initialized {
+		cached = cachedPipelines()
+	}
+	if len(cached) > 0 {
+		rows, _ := pipelinesTableRows(cached)
+		c.initialized = true
+		c.updateView(pipelinesView, pipelinesRows{rows: rows, stale: true, pipelines: cached})
+		background = true
+	}
+	if background {
+		c.revalidating = true
+	} else {
+		c.startSpinner()
+	}
+
+	var pipelines map[string]awsqueries.Pipeline
 	if config.Mode.Replay {
-		c.dataCache.pipelines, err = config.Recorder.ListCodePipelines("DataCachePipelines.json")
+		pipelines, err = config.Recorder.ListCodePipelines("DataCachePipelines.json")
 	} else {
-		c.dataCache.pipelines, err = awsqueries.CodePipelinesListFiltered(config.AwsConfig, config.awsAccountID, config.NameFilter, config.TagFilter)
-		config.Recorder.Record("DataCachePipelines.json", c.dataCache.pipelines)
+		pipelines, err = awsqueries.CodePipelinesListFiltered(config.AwsConfig, config.awsAccountID, config.NameFilter, config.TagFilter, describeOptions(tableColumns()))
+		config.Recorder.Record("DataCachePipelines.json", pipelines)
 	}
-	if err != nil {
+	if err != nil && !c.initialized {
 		log.Fatal().Err(err).Msgf("failed to list AWS CodePipeline: %v", err)
 	}
+	if err != nil {
+		// The displayed rows are kept until the next refresh
+		log.Debug().Str("model", "tui").Str("func", "pipelinesTableRefresh").Msgf("failed to list AWS CodePipeline: %v", err)
+		c.revalidating = false
+		c.listing = false
+		c.stopSpinner()
+		c.errorMsg(pipelinesView, fmt.Sprintf("failed to list AWS CodePipeline: %v", err))
+		return
+	}
+	savePipelinesCache(pipelines)
 
-	pipelines := c.dataCache.pipelines
-	rows := make([]table.Row, len(pipelines))
+	rows, failed := pipelinesTableRows(pipelines)
 
-	var userID, status, lastExecTime string
-	idx := 0
+	c.initialized = true
+	c.revalidating = false
+	c.listing = false
+	c.stopSpinner()
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		c.errorMsg(pipelinesView, fmt.Sprintf("%d pipelines could not be fully described, %v", len(failed), failed[0]))
+	}
+	c.updateView(pipelinesView, pipelinesRows{rows: rows, pipelines: pipelines})
+
+	names := make([]string, 0, len(pipelines))
+	for name := range pipelines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c.observe(pipelines[name])
+	}
+}
+
+// pipelinesTableRows returns the rows of the pipelines sorted by name and the errors of the pipelines not fully described
+func pipelinesTableRows(pipelines map[string]awsqueries.Pipeline) ([]table.Row, []string) {
+	rows := make([]table.Row, 0, len(pipelines))
+	columns := tableColumns()
+
+	var failed []string
 	for _, pipeline := range pipelines {
-		log.Debug().Str("model", "tui").Str("func", "pipelinesTableRefresh").Msgf("Pipeline: %v", pipeline.LastExecutionID)
-		if pipeline.LastExecutionID == "" {
-			userID = ""
-			status = "Unknown"
-			lastExecTime = ""
-		} else {
-			user := strings.Split(string(*pipeline.ExecData.PipelineExecutionSummaries[0].Trigger.TriggerDetail), "/")
-			userID = user[len(user)-1]
-			if strings.HasPrefix(userID, "AWSCodeBuild") {
-				userID = "CodeBuild"
-			}
-			status = string(pipeline.ExecData.PipelineExecutionSummaries[0].Status)
-			lastExecTime = PrintTime(pipeline.ExecData.PipelineExecutionSummaries[0].LastUpdateTime)
+		log.Debug().Str("model", "tui").Str("func", "pipelinesTableRows").Msgf("Pipeline: %v", pipeline.LastExecutionID)
+		if pipeline.Error != "" {
+			log.Debug().Str("model", "tui").Str("func", "pipelinesTableRows").Msgf("Pipeline %v error: %v", pipeline.PipelineName, pipeline.Error)
+			failed = append(failed, pipeline.PipelineName+": "+pipeline.Error)
 		}
-		rows[idx] = table.Row{
-			pipeline.PipelineName,
-			userID,
-			status,
-			lastExecTime,
-		}
-		idx++
+		rows = append(rows, pipelineRow(pipeline, columns))
 	}
 
 	if config.NameFilterExtra != "" {
 		rows = extraNameFilter(rows, config.NameFilterExtra)
 	}
 
-	// Sort CodePipelines by name
+	// Sort CodePipelines by name, the table applies the selected sort
 	sort.Slice(rows, func(i, j int) bool {
-		return rows[i][0] < rows[j][0]
+		return rowPipelineName(rows[i]) < rowPipelineName(rows[j])
 	})
 
-	c.initialized = true
-	c.stopSpinner()
-	c.updateView(pipelinesView, rows)
+	return rows, failed
+}
+
+// pipelinesQuery is a filter query of the pipelines view
+type pipelinesQuery struct {
+	text  string
+	query filterQuery
 }
 
 func (p *PipelinesTable) filterOperations(f string) {
-	rows := extraNameFilter(p.allRows, f)
-	p.ui.updateView(pipelinesView, slices.Clip(rows))
+	f = strings.TrimSpace(f)
+	q, err := parseFilterQuery(f)
+	if err != nil {
+		log.Debug().Str("model", "tui").Str("func", "filterOperations").Msgf("Invalid filter %q: %v", f, err)
+		p.ui.errorMsg(pipelinesView, fmt.Sprintf("Invalid filter: %v", err))
+		return
+	}
+	p.ui.updateView(pipelinesView, pipelinesQuery{text: f, query: q})
+}
+
+// filterRows returns the rows matching the filter query
+func (p *PipelinesTable) filterRows(rowsSrc []table.Row) []table.Row {
+	if p.query == nil {
+		return rowsSrc
+	}
+	rows := make([]table.Row, 0, len(rowsSrc))
+	for _, row := range rowsSrc {
+		if p.query.match(p.ui.dataCache.pipelines[rowPipelineName(row)], row) {
+			rows = append(rows, row)
+		}
+	}
+	return slices.Clip(rows)
 }
 
 func (p *PipelinesTable) browse() {
-	browser.OpenURL(fmt.Sprintf("https://%s.console.aws.amazon.com/codesuite/codepipeline/pipelines", config.AwsConfig.Region))
+	browser.OpenURL(awsqueries.ConsoleURL(config.AwsConfig.Region, "/codesuite/codepipeline/pipelines?region="+config.AwsConfig.Region))
 }
 
 func (p *PipelinesTable) start(pipelineName string) {
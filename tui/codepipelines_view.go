@@ -2,17 +2,21 @@ package tui
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/fabio42/codeplumber/models/table"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/rs/zerolog/log"
 )
 
 const (
-	pipelinesFilter = "pipelinesFilter"
+	pipelinesFilter   = "pipelinesFilter"
+	pipelinesSaveView = "pipelinesSaveView"
 )
 
 // PipelinesTable is the model for the pipelines table
@@ -23,34 +27,52 @@ type PipelinesTable struct {
 	width, height int
 	ui            *uiData
 	allRows       []table.Row
+	stale         bool
+	columns       []Column
+	sorting       pipelinesSorting
+	query         filterQuery
+	state         sessionState
+	savedView     int // index of the saved view displayed, -1 when none
+	// dashboard is true once the dashboard was opened on startup
+	dashboard bool
 }
 
 // NewPipelinesTable returns a new PipelinesTable
 func NewPipelinesTable(ui *uiData) *PipelinesTable {
-	t := table.New()
-	t.SetStyles(ui.getTablePatchedStyle())
-	return &PipelinesTable{
-		Model: &t,
-		name:  "codepipelines",
-		ui:    ui,
-		help:  help.New(),
+	t := table.New(table.WithKeyMap(tableKeys))
+	m := &PipelinesTable{
+		Model:     &t,
+		name:      "codepipelines",
+		ui:        ui,
+		help:      help.New(),
+		columns:   tableColumns(),
+		state:     readState(),
+		savedView: -1,
 	}
+	// The sort is validated with the configuration
+	m.sorting, _ = parseSorting(config.Sort, m.columns)
+
+	// Rows loaded from the on-disk cache are dimmed until the listing is refreshed
+	s := ui.getTablePatchedStyle()
+	renderCell := s.RenderCell
+	s.RenderCell = func(model table.Model, value string, position table.CellPosition) string {
+		if m.stale && !position.IsRowSelected {
+			return s.Cell.Foreground(lipgloss.Color("240")).Italic(true).Render(value)
+		}
+		return renderCell(model, value, position)
+	}
+	t.SetStyles(s)
+	return m
 }
 
 // SetColumns set the columns of the table
 func (m *PipelinesTable) SetColumns(width int) {
-	width = width - 5
-	triggerSize := percent(width, 25, 20)
-	statusSize := percent(width, 25, 10)
-	lastExecutionSize := percent(width, 40, 22)
-	nameSize := width - statusSize - lastExecutionSize - triggerSize
-
-	cols := make([]table.Column, 4)
-	cols[0] = table.Column{Title: "CodePipeline Name", Width: nameSize}
-	cols[1] = table.Column{Title: "User", Width: triggerSize}
-	cols[2] = table.Column{Title: "Status", Width: statusSize}
-	cols[3] = table.Column{Title: "Last execution", Width: lastExecutionSize}
-	m.Model.SetColumns(cols)
+	width = width - len(m.columns)
+	columns := pipelinesTableColumns(m.columns, width, m.sorting)
+	if m.ui.split {
+		columns = compactColumns(columns, m.columns, width)
+	}
+	m.Model.SetColumns(columns)
 	m.Focus()
 }
 
@@ -87,9 +109,7 @@ func (m *PipelinesTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, allKeys.Select):
-			if len(m.SelectedRow()) > 0 {
-				m.ui.changeView(pipelinesView, pipelineView, m.SelectedRow()[0])
-			}
+			m.open()
 
 		case key.Matches(msg, codePipelineKeys.Start):
 			if len(m.SelectedRow()) > 0 {
@@ -99,6 +119,33 @@ func (m *PipelinesTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, allKeys.Refresh):
 			m.refresh()
 
+		case key.Matches(msg, pipelinesKeys.Sort):
+			m.sorting = m.sorting.next(m.columns)
+			m.setRows(m.filterRows(m.allRows))
+
+		case key.Matches(msg, pipelinesKeys.SortReverse):
+			m.sorting.reverse = !m.sorting.reverse
+			m.setRows(m.filterRows(m.allRows))
+
+		case key.Matches(msg, pipelinesKeys.Pin):
+			if len(m.SelectedRow()) > 0 {
+				m.state.togglePin(rowPipelineName(m.SelectedRow()))
+				m.saveState()
+				m.setRows(m.filterRows(m.allRows))
+			}
+
+		case key.Matches(msg, pipelinesKeys.SaveView):
+			m.ui.requestInput(pipelinesSaveView, "text", "SAVE view as NAME (empty to cancel):", nil)
+
+		case key.Matches(msg, pipelinesKeys.NextView):
+			m.nextView()
+
+		case key.Matches(msg, pipelinesKeys.DeleteView):
+			m.deleteView()
+
+		case key.Matches(msg, pipelinesKeys.Dashboard):
+			m.ui.changeView(pipelinesView, dashboardView, m.rowNames())
+
 		case key.Matches(msg, allKeys.Search):
 			m.ui.search(pipelinesFilter)
 
@@ -109,6 +156,12 @@ func (m *PipelinesTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case redraw:
 		m.UpdateViewport()
 
+	case refresh:
+		m.revalidate()
+
+	case table.SelectMsg:
+		m.open()
+
 	case tuiMsg:
 		log.Debug().Str("model", "tui").Str("func", "PipelinesTable.Update").Msgf("tuiMsg class: %v, id: %v, trigger: %v, data: %v", msg.class, msg.id, msg.trigger, msg.data)
 		switch msg.class {
@@ -116,18 +169,37 @@ func (m *PipelinesTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.src {
 			case pipelineStart:
 				if msg.trigger {
-					m.start(m.SelectedRow()[0])
+					m.start(rowPipelineName(m.SelectedRow()))
 					m.refresh()
 				}
 			case pipelinesFilter:
 				go m.filterOperations(msg.data.(string))
+			case pipelinesSaveView:
+				if msg.trigger {
+					m.saveView(msg.data.(string))
+				}
 			}
 		case viewUpdate:
-			rows := msg.data.([]table.Row)
-			m.SetColumns(m.width)
-			m.SetRows(rows)
-			if len(m.allRows) == 0 {
-				m.allRows = rows
+			var rows []table.Row
+			switch data := msg.data.(type) {
+			case pipelinesRows:
+				if data.pipelines != nil {
+					m.ui.mergePipelines(data.pipelines)
+				}
+				// The filter query applies to the refreshed rows
+				rows = m.filterRows(data.rows)
+				m.allRows = data.rows
+				m.stale = data.stale
+			case pipelinesQuery:
+				m.query = data.query
+				m.ui.addQuery(data.text)
+				m.savedView = -1
+				m.ui.savedView = ""
+				rows = m.filterRows(m.allRows)
+			}
+			m.setRows(rows)
+			if _, ok := msg.data.(pipelinesRows); ok {
+				m.openDashboard()
 			}
 
 		default:
@@ -136,8 +208,42 @@ func (m *PipelinesTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	*m.Model, _ = m.Model.Update(msg)
-	return m, tea.Batch(cmds...)
+	var cmd tea.Cmd
+	*m.Model, cmd = m.Model.Update(msg)
+	return m, tea.Batch(append(cmds, cmd)...)
+}
+
+// open opens the pipeline of the selected row
+func (m *PipelinesTable) open() {
+	if len(m.SelectedRow()) > 0 {
+		m.ui.changeView(pipelinesView, pipelineView, rowPipelineName(m.SelectedRow()))
+	}
+}
+
+// rowNames returns the names of the pipelines displayed, in the order of the table
+func (m *PipelinesTable) rowNames() []string {
+	names := make([]string, len(m.Rows()))
+	for i, row := range m.Rows() {
+		names[i] = rowPipelineName(row)
+	}
+	return names
+}
+
+// openDashboard opens the dashboard on startup once the pipelines are listed
+func (m *PipelinesTable) openDashboard() {
+	if !config.Dashboard || m.dashboard || m.stale || m.ui.currentView() != pipelinesView {
+		return
+	}
+	m.dashboard = true
+	go m.ui.changeView(pipelinesView, dashboardView, m.rowNames())
+}
+
+// setRows sorts and displays the filtered rows, the pinned pipelines first even when the filter excludes them
+func (m *PipelinesTable) setRows(rows []table.Row) {
+	sortPipelineRows(m.allRows, m.ui.dataCache.pipelines, m.columns, m.sorting)
+	sortPipelineRows(rows, m.ui.dataCache.pipelines, m.columns, m.sorting)
+	m.SetColumns(m.width)
+	m.SetRows(m.state.pinnedFirst(m.allRows, rows))
 }
 
 // View implement the tea.Model interface
@@ -170,10 +276,29 @@ func (m *PipelinesTable) helpViewFull() string {
 			allKeys.Select,
 			allKeys.Previous,
 		},
+		{
+			tableKeys.PageDown,
+			tableKeys.PageUp,
+			tableKeys.GotoTop,
+			tableKeys.GotoBottom,
+		},
 		{
 			allKeys.Browse,
 			allKeys.Search,
 			codePipelineKeys.Start,
+			allKeys.Split,
+			allKeys.Palette,
+		},
+		{
+			pipelinesKeys.Sort,
+			pipelinesKeys.SortReverse,
+			pipelinesKeys.Pin,
+		},
+		{
+			pipelinesKeys.SaveView,
+			pipelinesKeys.NextView,
+			pipelinesKeys.DeleteView,
+			pipelinesKeys.Dashboard,
 		},
 		{
 			allKeys.Refresh,
@@ -182,3 +307,89 @@ func (m *PipelinesTable) helpViewFull() string {
 		},
 	})
 }
+
+// saveState writes the pins and the saved views, errors are reported without interrupting the session
+func (m *PipelinesTable) saveState() {
+	if err := m.state.save(); err != nil {
+		log.Debug().Str("model", "tui").Str("func", "PipelinesTable.saveState").Msgf("failed to save state %v: %v", config.StatePath, err)
+		go m.ui.errorMsg(pipelinesView, fmt.Sprintf("Failed to save state: %v", err))
+	}
+}
+
+// saveView saves the filter query and the sort as a named view
+func (m *PipelinesTable) saveView(name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+	m.savedView = m.state.saveView(savedView{
+		Name:  name,
+		Query: m.ui.query,
+		Sort:  m.sorting.String(m.columns),
+	})
+	m.ui.savedView = name
+	m.saveState()
+}
+
+// nextView displays the next saved view, then all the pipelines with the configured sort
+func (m *PipelinesTable) nextView() {
+	if len(m.state.Views) == 0 {
+		go m.ui.infoMsg(pipelinesView, fmt.Sprintf("No saved view, press %v to save the filter and the sort.", pipelinesKeys.SaveView.Help().Key))
+		return
+	}
+
+	idx := m.savedView + 1
+	if idx >= len(m.state.Views) {
+		idx = -1
+	}
+	// An invalid view is skipped on the next press
+	m.savedView = idx
+	m.showView(idx)
+}
+
+// showView displays a saved view, all the CodePipelines with the configured sort when idx is -1
+func (m *PipelinesTable) showView(idx int) {
+	view := savedView{Sort: config.Sort}
+	if idx >= 0 {
+		view = m.state.Views[idx]
+	}
+
+	query, err := parseFilterQuery(view.Query)
+	if err != nil {
+		go m.ui.errorMsg(pipelinesView, fmt.Sprintf("Invalid filter of view %v: %v", view.Name, err))
+		return
+	}
+	sorting, err := parseSorting(view.Sort, m.columns)
+	if err != nil {
+		go m.ui.errorMsg(pipelinesView, fmt.Sprintf("Invalid sort of view %v: %v", view.Name, err))
+		return
+	}
+	m.savedView = idx
+	m.query = query
+	m.sorting = sorting
+	m.ui.addQuery(view.Query)
+	m.ui.savedView = view.Name
+	m.setRows(m.filterRows(m.allRows))
+}
+
+// selectPipeline moves the cursor to a CodePipeline, the cursor is kept when it is not listed
+func (m *PipelinesTable) selectPipeline(name string) {
+	for i, row := range m.Rows() {
+		if rowPipelineName(row) == name {
+			m.SetCursor(i)
+			return
+		}
+	}
+}
+
+// deleteView deletes the saved view displayed
+func (m *PipelinesTable) deleteView() {
+	if m.savedView < 0 || m.savedView >= len(m.state.Views) {
+		go m.ui.infoMsg(pipelinesView, "No saved view displayed.")
+		return
+	}
+	m.state.Views = slices.Delete(m.state.Views, m.savedView, m.savedView+1)
+	m.savedView = -1
+	m.ui.savedView = ""
+	m.saveState()
+}
@@ -0,0 +1,436 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+	"github.com/fabio42/codeplumber/models/table"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline/types"
+)
+
+// Column is a column of the pipelines table configured in a profile
+type Column struct {
+	// Name is the name of the column in pipelinesColumns
+	Name string
+	// Width is the percentage of the table width used by the column, the default width when 0
+	Width int
+	// Tag is the tag displayed by the tag column
+	Tag string
+}
+
+// pipelinesColumn describes how a column of the pipelines table is rendered and sorted
+type pipelinesColumn struct {
+	title string
+	// width is the default percentage of the table width and max the maximum width, the name column uses the remaining width
+	width, max int
+	value      func(p awsqueries.Pipeline, c Column) string
+	// key orders the rows when the column is sorted, the value is used when nil
+	key func(p awsqueries.Pipeline, c Column) string
+	// descending columns are sorted in descending order first, e.g. the most recent executions
+	descending bool
+	// needs are the optional data the column requires from the pipelines description
+	needs awsqueries.DescribeOptions
+}
+
+// defaultColumns are the columns displayed when the profile does not configure them
+var defaultColumns = []Column{{Name: "name"}, {Name: "user"}, {Name: "status"}, {Name: "lastExecution"}, {Name: "changes"}}
+
+// failedSort orders the failed pipelines first, the most recent failures first
+const failedSort = "failed"
+
+var pipelinesColumns = map[string]pipelinesColumn{
+	"name": {
+		title: "CodePipeline Name",
+		value: func(p awsqueries.Pipeline, _ Column) string { return p.PipelineName },
+	},
+	"user": {
+		title: "User",
+		width: 25,
+		max:   20,
+		value: func(p awsqueries.Pipeline, _ Column) string {
+			summary := lastExecution(p)
+			if summary == nil || summary.Trigger == nil {
+				return ""
+			}
+			user := strings.Split(aws.ToString(summary.Trigger.TriggerDetail), "/")
+			if strings.HasPrefix(user[len(user)-1], "AWSCodeBuild") {
+				return "CodeBuild"
+			}
+			return user[len(user)-1]
+		},
+	},
+	"status": {
+		title: "Status",
+		width: 25,
+		max:   10,
+		value: func(p awsqueries.Pipeline, _ Column) string { return pipelineStatus(p) },
+		key: func(p awsqueries.Pipeline, _ Column) string {
+			return fmt.Sprintf("%d", statusRank(pipelineStatus(p)))
+		},
+	},
+	"lastExecution": {
+		title: "Last execution",
+		width: 40,
+		max:   22,
+		value: func(p awsqueries.Pipeline, _ Column) string {
+			if summary := lastExecution(p); summary != nil && summary.LastUpdateTime != nil {
+				return PrintTime(summary.LastUpdateTime)
+			}
+			return ""
+		},
+		descending: true,
+	},
+	"changes": {
+		title: "Changes",
+		width: 30,
+		value: func(p awsqueries.Pipeline, _ Column) string {
+			if summary := lastExecution(p); summary != nil {
+				return revisionsChanges(summary.SourceRevisions)
+			}
+			return p.Error
+		},
+	},
+	"duration": {
+		title: "Duration",
+		width: 15,
+		max:   10,
+		value: func(p awsqueries.Pipeline, _ Column) string {
+			if d, ok := executionDuration(p); ok {
+				return d.String()
+			}
+			return ""
+		},
+		key: func(p awsqueries.Pipeline, _ Column) string {
+			d, _ := executionDuration(p)
+			return fmt.Sprintf("%020d", d)
+		},
+		descending: true,
+	},
+	"stage": {
+		title: "Stage",
+		width: 20,
+		max:   20,
+		value: func(p awsqueries.Pipeline, _ Column) string { return p.CurrentStage },
+		needs: awsqueries.DescribeOptions{State: true},
+	},
+	"lastSuccess": {
+		title: "Last success",
+		width: 40,
+		max:   22,
+		value: func(p awsqueries.Pipeline, _ Column) string {
+			if p.LastSuccess != nil && p.LastSuccess.LastUpdateTime != nil {
+				return PrintTime(p.LastSuccess.LastUpdateTime)
+			}
+			return ""
+		},
+		descending: true,
+		needs:      awsqueries.DescribeOptions{LastSuccess: true},
+	},
+	"account": {
+		title: "Account",
+		width: 15,
+		max:   14,
+		value: func(_ awsqueries.Pipeline, _ Column) string { return config.awsAccountID },
+	},
+	"region": {
+		title: "Region",
+		width: 15,
+		max:   16,
+		value: func(_ awsqueries.Pipeline, _ Column) string { return config.AwsConfig.Region },
+	},
+	"tags": {
+		title: "Tags",
+		width: 30,
+		value: func(p awsqueries.Pipeline, _ Column) string {
+			tags := make([]string, 0, len(p.Tags))
+			for k, v := range p.Tags {
+				tags = append(tags, k+"="+v)
+			}
+			sort.Strings(tags)
+			return strings.Join(tags, ",")
+		},
+	},
+	"tag": {
+		width: 15,
+		max:   20,
+		value: func(p awsqueries.Pipeline, c Column) string { return p.Tags[c.Tag] },
+	},
+}
+
+// ValidateColumns returns an error when a column is unknown or misconfigured, or the sort does not match a column
+func ValidateColumns(columns []Column, sort string) error {
+	if len(columns) == 0 {
+		columns = defaultColumns
+	}
+	for _, c := range columns {
+		if _, ok := pipelinesColumns[c.Name]; !ok {
+			names := make([]string, 0, len(pipelinesColumns))
+			for name := range pipelinesColumns {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			return fmt.Errorf("unknown column %q, supported columns: %v", c.Name, strings.Join(names, ", "))
+		}
+		if c.Name == "tag" && c.Tag == "" {
+			return fmt.Errorf("the tag column requires a tag")
+		}
+		if c.Width < 0 || c.Width > 100 {
+			return fmt.Errorf("invalid width of column %v: %d", c.Name, c.Width)
+		}
+	}
+	_, err := parseSorting(sort, columns)
+	return err
+}
+
+// tableColumns returns the columns of the pipelines table configured in the profile
+func tableColumns() []Column {
+	if len(config.Columns) == 0 {
+		return defaultColumns
+	}
+	return config.Columns
+}
+
+// describeOptions returns the optional data required by the columns
+func describeOptions(columns []Column) awsqueries.DescribeOptions {
+	opts := awsqueries.DescribeOptions{Concurrency: config.Concurrency}
+	for _, c := range columns {
+		needs := pipelinesColumns[c.Name].needs
+		opts.State = opts.State || needs.State
+		opts.LastSuccess = opts.LastSuccess || needs.LastSuccess
+	}
+	return opts
+}
+
+// pipelinesTableColumns returns the columns of the table, the hidden last column holds the pipeline name
+// The sorted column shows the sort direction
+func pipelinesTableColumns(columns []Column, width int, sorting pipelinesSorting) []table.Column {
+	cols := make([]table.Column, len(columns)+1)
+
+	fill, used := -1, 0
+	for i, c := range columns {
+		column := pipelinesColumns[c.Name]
+		title := column.title
+		if c.Name == "tag" {
+			title = c.Tag
+		}
+		switch {
+		case sorting.column == i:
+			title += " " + sorting.indicator(column.descending)
+		case sorting.column == -1 && c.Name == "status":
+			title += " " + sorting.indicator(true)
+		}
+
+		size := percent(width, column.width, column.max)
+		if c.Width > 0 {
+			size = percent(width, c.Width, 0)
+		}
+		// The first column without a default width fills the remaining space
+		if fill == -1 && column.width == 0 && c.Width == 0 {
+			fill = i
+			size = 0
+		}
+		used += size
+		cols[i] = table.Column{Title: title, Width: size}
+	}
+	if fill != -1 {
+		cols[fill].Width = max(0, width-used)
+	}
+	cols[len(columns)] = table.Column{Title: "Name", Width: 0}
+	return cols
+}
+
+// pipelineRow returns the row of a pipeline, the hidden last column holds the pipeline name
+func pipelineRow(p awsqueries.Pipeline, columns []Column) table.Row {
+	row := make(table.Row, len(columns)+1)
+	for i, c := range columns {
+		row[i] = pipelinesColumns[c.Name].value(p, c)
+	}
+	row[len(columns)] = p.PipelineName
+	return row
+}
+
+// rowPipelineName returns the pipeline name of a row of the pipelines table
+func rowPipelineName(row table.Row) string {
+	if len(row) == 0 {
+		return ""
+	}
+	return row[len(row)-1]
+}
+
+// pipelinesSorting is the order of the pipelines table
+type pipelinesSorting struct {
+	// column is the index of the sorted column, -1 for the failed pipelines first
+	column  int
+	reverse bool
+}
+
+// parseSorting returns the sorting configured in a profile: a column name, optionally prefixed with '-' to reverse the order, or "failed"
+func parseSorting(s string, columns []Column) (pipelinesSorting, error) {
+	name, reverse := strings.CutPrefix(s, "-")
+	switch name {
+	case "":
+		return pipelinesSorting{}, nil
+	case failedSort:
+		return pipelinesSorting{column: -1, reverse: reverse}, nil
+	}
+	for i, c := range columns {
+		if c.Name == name || (c.Name == "tag" && "tag:"+c.Tag == name) {
+			return pipelinesSorting{column: i, reverse: reverse}, nil
+		}
+	}
+	return pipelinesSorting{}, fmt.Errorf("unknown sort %q, the column is not displayed", s)
+}
+
+// String returns the sorting in the format of parseSorting
+func (s pipelinesSorting) String(columns []Column) string {
+	var name string
+	switch {
+	case s.column == -1:
+		name = failedSort
+	case s.column < len(columns) && columns[s.column].Name == "tag":
+		name = "tag:" + columns[s.column].Tag
+	case s.column < len(columns):
+		name = columns[s.column].Name
+	}
+	if s.reverse {
+		return "-" + name
+	}
+	return name
+}
+
+// next returns the sorting by the following column, the failed pipelines first after the last column
+func (s pipelinesSorting) next(columns []Column) pipelinesSorting {
+	switch {
+	case s.column == -1:
+		return pipelinesSorting{}
+	case s.column+1 < len(columns):
+		return pipelinesSorting{column: s.column + 1}
+	default:
+		return pipelinesSorting{column: -1}
+	}
+}
+
+// indicator shows the sort direction, descending columns are sorted in descending order unless reversed
+func (s pipelinesSorting) indicator(descending bool) string {
+	arrow := "▲"
+	if s.reverse != descending {
+		arrow = "▼"
+	}
+	if s.column == -1 {
+		return "✗" + arrow
+	}
+	return arrow
+}
+
+// sortPipelineRows sorts the rows of the pipelines table, ties are ordered by name
+func sortPipelineRows(rows []table.Row, pipelines map[string]awsqueries.Pipeline, columns []Column, s pipelinesSorting) {
+	// compareKeys orders the keys, empty keys last
+	compareKeys := func(a, b string, descending bool) int {
+		switch {
+		case a == b:
+			return 0
+		case a == "":
+			return 1
+		case b == "":
+			return -1
+		case descending:
+			return strings.Compare(b, a)
+		default:
+			return strings.Compare(a, b)
+		}
+	}
+
+	compare := func(a, b awsqueries.Pipeline) int {
+		if s.column == -1 {
+			// Failed pipelines first, the most recent failure first
+			failedA, failedB := statusRank(pipelineStatus(a)) == 0, statusRank(pipelineStatus(b)) == 0
+			if failedA != failedB {
+				if failedA {
+					return -1
+				}
+				return 1
+			}
+			lastExecution := pipelinesColumns["lastExecution"].value
+			return compareKeys(lastExecution(a, Column{}), lastExecution(b, Column{}), true)
+		}
+		if s.column >= len(columns) {
+			return 0
+		}
+		c := columns[s.column]
+		column := pipelinesColumns[c.Name]
+		key := column.value
+		if column.key != nil {
+			key = column.key
+		}
+		return compareKeys(key(a, c), key(b, c), column.descending)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		nameI, nameJ := rowPipelineName(rows[i]), rowPipelineName(rows[j])
+		cmp := compare(pipelines[nameI], pipelines[nameJ])
+		if s.reverse {
+			cmp = -cmp
+		}
+		if cmp == 0 {
+			return nameI < nameJ
+		}
+		return cmp < 0
+	})
+}
+
+// statusRank orders the statuses from the most to the least important
+func statusRank(status string) int {
+	switch strings.ToLower(status) {
+	case "failed", "error":
+		return 0
+	case "stopped", "stopping":
+		return 1
+	case "inprogress":
+		return 2
+	case "superseded", "cancelled":
+		return 3
+	case "succeeded":
+		return 4
+	default:
+		return 5
+	}
+}
+
+// pipelineStatus returns the status of the last execution of a pipeline
+func pipelineStatus(p awsqueries.Pipeline) string {
+	summary := lastExecution(p)
+	switch {
+	case summary != nil:
+		return string(summary.Status)
+	case p.Error != "":
+		return "Error"
+	default:
+		return "Unknown"
+	}
+}
+
+func lastExecution(p awsqueries.Pipeline) *types.PipelineExecutionSummary {
+	if p.LastExecutionID == "" || p.ExecData == nil || len(p.ExecData.PipelineExecutionSummaries) == 0 {
+		return nil
+	}
+	return &p.ExecData.PipelineExecutionSummaries[0]
+}
+
+// executionDuration returns the duration of the last execution, executions in progress are running since their start
+func executionDuration(p awsqueries.Pipeline) (time.Duration, bool) {
+	summary := lastExecution(p)
+	if summary == nil || summary.StartTime == nil {
+		return 0, false
+	}
+	end := time.Now()
+	if summary.Status != types.PipelineExecutionStatusInProgress && summary.LastUpdateTime != nil {
+		end = *summary.LastUpdateTime
+	}
+	return end.Sub(*summary.StartTime).Round(time.Second), true
+}
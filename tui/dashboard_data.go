@@ -0,0 +1,155 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline/types"
+	"github.com/charmbracelet/lipgloss"
+	tint "github.com/lrstanley/bubbletint"
+	"github.com/mattn/go-runewidth"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// dashboardMinCell and dashboardMaxCell bound the width of the stage cells
+	dashboardMinCell = 3
+	dashboardMaxCell = 18
+)
+
+// dashboardData are the pipelines described with the state of their stages
+type dashboardData struct {
+	pipelines []awsqueries.Pipeline
+	updated   time.Time
+}
+
+// refreshDashboard describes the pipelines of the dashboard with the state of their stages, tagged are their cached tags
+// The spinner is only displayed on the first load, the dashboard remains usable while it is refreshed
+func refreshDashboard(c *uiData, names []string, tagged map[string]map[string]string, spinner bool) {
+	if config.Mode.Replay {
+		c.updateView(dashboardView, dashboardData{updated: time.Now()})
+		return
+	}
+	if spinner {
+		c.startSpinner()
+		defer c.stopSpinner()
+	}
+
+	opts := describeOptions(tableColumns())
+	opts.State = true
+	opts.Concurrency = config.Concurrency
+
+	pipelines := awsqueries.DescribePipelines(config.AwsConfig, config.awsAccountID, names, tagged, opts)
+	log.Debug().Str("model", "tui").Str("func", "refreshDashboard").Msgf("described %v pipelines", len(pipelines))
+	c.updateView(dashboardView, dashboardData{pipelines: pipelines, updated: time.Now()})
+	c.observe(pipelines...)
+}
+
+// dashboardStatusColor returns the background color of a stage or pipeline status, nil when it never ran
+func dashboardStatusColor(status string) lipgloss.TerminalColor {
+	switch status {
+	case string(types.StageExecutionStatusInProgress):
+		return tint.Blue()
+	case string(types.StageExecutionStatusSucceeded):
+		return tint.Green()
+	case string(types.StageExecutionStatusFailed):
+		return tint.Red()
+	case string(types.StageExecutionStatusStopped), string(types.StageExecutionStatusStopping):
+		return tint.Yellow()
+	case string(types.StageExecutionStatusCancelled), string(types.PipelineExecutionStatusSuperseded):
+		return lipgloss.Color("244")
+	case "":
+		return nil
+	default:
+		return lipgloss.Color("240")
+	}
+}
+
+// stageStatus returns the status of the latest execution of a stage, empty when it never ran
+func stageStatus(stage types.StageState) string {
+	if stage.LatestExecution == nil {
+		return ""
+	}
+	return string(stage.LatestExecution.Status)
+}
+
+// dashboardCellWidth returns the width of the stage cells to fit the pipeline with the most stages
+func dashboardCellWidth(width, stages int) int {
+	if stages == 0 {
+		return dashboardMaxCell
+	}
+	// Cells are separated by a space
+	cell := (width - stages + 1) / stages
+	return max(dashboardMinCell, min(dashboardMaxCell, cell))
+}
+
+// renderStageCell renders a stage as a cell colored by its status
+// A lighter band sweeps through the cells of the stages in progress, frame is the animation step
+func renderStageCell(stage types.StageState, width, frame int) string {
+	name := runewidth.Truncate(aws.ToString(stage.StageName), width, "…")
+	name = runewidth.FillRight(name, width)
+
+	status := stageStatus(stage)
+	color := dashboardStatusColor(status)
+	if color == nil {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Background(lipgloss.Color("236")).Render(name)
+	}
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("232")).Background(color)
+	if status != string(types.StageExecutionStatusInProgress) {
+		return style.Render(name)
+	}
+
+	// The band is 2 cells wide and moves every 2 frames
+	pos := (frame / 2) % width
+	highlight := style.Background(tint.BrightBlue()).Bold(true)
+	var cell strings.Builder
+	for i, r := range []rune(name) {
+		if i == pos || i == (pos+1)%width {
+			cell.WriteString(highlight.Render(string(r)))
+		} else {
+			cell.WriteString(style.Render(string(r)))
+		}
+	}
+	return cell.String()
+}
+
+// dashboardSummary renders the number of pipelines per status of their last execution
+func dashboardSummary(pipelines []awsqueries.Pipeline, updated time.Time) string {
+	counts := map[string]int{}
+	for _, p := range pipelines {
+		status := p.LastExecutionStatus
+		if status == "" {
+			status = "Unknown"
+		}
+		counts[status]++
+	}
+	statuses := make([]string, 0, len(counts))
+	for status := range counts {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if rankI, rankJ := statusRank(statuses[i]), statusRank(statuses[j]); rankI != rankJ {
+			return rankI < rankJ
+		}
+		return statuses[i] < statuses[j]
+	})
+
+	bold := lipgloss.NewStyle().Bold(true)
+	summary := []string{bold.Render(fmt.Sprintf("%d Pipelines", len(pipelines)))}
+	for _, status := range statuses {
+		dot := "●"
+		if color := dashboardStatusColor(status); color != nil {
+			dot = lipgloss.NewStyle().Foreground(color).Render(dot)
+		}
+		summary = append(summary, fmt.Sprintf("%v %v %d", dot, status, counts[status]))
+	}
+	if !updated.IsZero() {
+		summary = append(summary, lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render("updated "+updated.Format("15:04:05")))
+	}
+	return strings.Join(summary, "   ")
+}
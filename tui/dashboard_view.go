@@ -0,0 +1,255 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	tint "github.com/lrstanley/bubbletint"
+	"github.com/mattn/go-runewidth"
+	"github.com/rs/zerolog/log"
+)
+
+// dashboardHeaderHeight is the height of the summary header and its separator
+const dashboardHeaderHeight = 2
+
+// Dashboard is a one-screen overview of the pipelines and the status of their stages
+type Dashboard struct {
+	help          help.Model
+	name          string
+	width, height int
+	ui            *uiData
+	// names are the pipelines displayed, in the order of the pipelines table
+	names   []string
+	cursor  int
+	offset  int
+	frame   int
+	updated time.Time
+}
+
+// NewDashboard returns a new Dashboard
+func NewDashboard(ui *uiData) *Dashboard {
+	return &Dashboard{
+		name: "dashboard",
+		ui:   ui,
+		help: help.New(),
+	}
+}
+
+// Init implement the tea.Model interface
+func (m *Dashboard) Init() tea.Cmd {
+	return nil
+}
+
+// Update implement the tea.Model interface
+func (m *Dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m.ui.updatPath(m.name)
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		verticalMarginHeight := 2
+		if m.ui.help {
+			verticalMarginHeight += helpFullHeiggt
+		} else {
+			verticalMarginHeight += helpHeight
+		}
+		m.width = msg.Width - 2
+		m.height = msg.Height - verticalMarginHeight
+		m.scroll()
+
+	case spinner.TickMsg:
+		// The stages in progress are animated on the ticks of the spinner
+		m.frame++
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, allKeys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			m.scroll()
+
+		case key.Matches(msg, allKeys.Down):
+			if m.cursor < len(m.names)-1 {
+				m.cursor++
+			}
+			m.scroll()
+
+		case key.Matches(msg, allKeys.Select):
+			if m.cursor < len(m.names) {
+				m.ui.changeView(dashboardView, pipelineView, m.names[m.cursor])
+			}
+
+		case key.Matches(msg, allKeys.Previous):
+			m.ui.previousView()
+
+		case key.Matches(msg, allKeys.Refresh):
+			m.refresh(m.names, true)
+		}
+
+	case refresh:
+		m.refresh(m.names, false)
+
+	case tuiMsg:
+		switch msg.class {
+		case viewChange:
+			m.names = msg.data.([]string)
+			m.cursor, m.offset = 0, 0
+			m.refresh(m.names, true)
+
+		case viewUpdate:
+			data := msg.data.(dashboardData)
+			for _, p := range data.pipelines {
+				if p.Error != "" {
+					log.Debug().Str("model", "tui").Str("func", "Dashboard.Update").Msgf("keeping %v: %v", p.PipelineName, p.Error)
+					continue
+				}
+				// The definition is only described by the pipeline view
+				p.Data = m.ui.dataCache.pipelines[p.PipelineName].Data
+				m.ui.dataCache.pipelines[p.PipelineName] = p
+			}
+			m.updated = data.updated
+
+		case eventMsg:
+			for _, name := range m.names {
+				if name == msg.data.(string) {
+					m.refresh([]string{name}, false)
+				}
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *Dashboard) refresh(names []string, spinner bool) {
+	if len(names) == 0 {
+		return
+	}
+	// Tags are read from the data cache on the Update loop
+	tagged := make(map[string]map[string]string, len(names))
+	for _, name := range names {
+		if tags := m.ui.dataCache.pipelines[name].Tags; tags != nil {
+			tagged[name] = tags
+		}
+	}
+	go refreshDashboard(m.ui, names, tagged, spinner)
+}
+
+// rows returns the number of pipelines displayed at once
+func (m *Dashboard) rows() int {
+	return max(1, m.height-dashboardHeaderHeight)
+}
+
+// scroll keeps the cursor visible
+func (m *Dashboard) scroll() {
+	switch {
+	case m.cursor < m.offset:
+		m.offset = m.cursor
+	case m.cursor >= m.offset+m.rows():
+		m.offset = m.cursor - m.rows() + 1
+	}
+}
+
+// View implement the tea.Model interface
+func (m *Dashboard) View() string {
+	var help string
+	if m.ui.help {
+		help = m.helpViewFull()
+	} else {
+		help = m.helpView()
+	}
+	return fmt.Sprintf("%s\n%s", m.dashboardView(), help)
+}
+
+func (m *Dashboard) dashboardView() string {
+	var b strings.Builder
+
+	described := make([]awsqueries.Pipeline, 0, len(m.names))
+	nameWidth, stages := 0, 0
+	for _, name := range m.names {
+		// Pipelines removed since the dashboard was opened are displayed without state
+		p := m.ui.dataCache.pipelines[name]
+		p.PipelineName = name
+		described = append(described, p)
+		nameWidth = max(nameWidth, runewidth.StringWidth(name))
+		if p.StateData != nil {
+			stages = max(stages, len(p.StateData.StageStates))
+		}
+	}
+	// Names take at most a third of the width, the cursor and the status take 4 cells
+	nameWidth = min(nameWidth, m.width/3)
+	cellWidth := dashboardCellWidth(m.width-nameWidth-4, stages)
+
+	b.WriteString(dashboardSummary(described, m.updated))
+	b.WriteString("\n\n")
+
+	end := min(len(described), m.offset+m.rows())
+	for i := m.offset; i < end; i++ {
+		p := described[i]
+		cursor := "  "
+		nameStyle := lipgloss.NewStyle()
+		if i == m.cursor {
+			cursor = "▶ "
+			nameStyle = nameStyle.Bold(true).Foreground(tint.Purple())
+		}
+		name := runewidth.FillRight(runewidth.Truncate(p.PipelineName, nameWidth, "…"), nameWidth)
+
+		status := " "
+		if color := dashboardStatusColor(p.LastExecutionStatus); color != nil {
+			status = lipgloss.NewStyle().Foreground(color).Render("●")
+		}
+
+		cells := []string{}
+		if p.StateData == nil {
+			cells = append(cells, lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render("…"))
+		} else {
+			for _, stage := range p.StateData.StageStates {
+				cells = append(cells, renderStageCell(stage, cellWidth, m.frame))
+			}
+		}
+
+		line := cursor + nameStyle.Render(name) + " " + status + " " + strings.Join(cells, " ")
+		b.WriteString(lipgloss.NewStyle().MaxWidth(m.width).Render(line))
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	// The help stays at the bottom of the screen
+	for i := end - m.offset; i < m.rows(); i++ {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func (m *Dashboard) helpView() string {
+	return m.help.ShortHelpView([]key.Binding{
+		allKeys.Select,
+		allKeys.Previous,
+		allKeys.Refresh,
+		allKeys.Help,
+	})
+}
+
+func (m *Dashboard) helpViewFull() string {
+	return m.help.FullHelpView([][]key.Binding{
+		{
+			allKeys.Up,
+			allKeys.Down,
+			allKeys.Select,
+			allKeys.Previous,
+		},
+		{
+			allKeys.Refresh,
+			allKeys.Quit,
+			allKeys.Help,
+		},
+	})
+}
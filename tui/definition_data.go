@@ -0,0 +1,247 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+	"github.com/fabio42/codeplumber/pipeline"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline"
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline/types"
+	"github.com/charmbracelet/lipgloss"
+	tint "github.com/lrstanley/bubbletint"
+	"github.com/rs/zerolog/log"
+)
+
+// Formats of the pipeline definition
+const (
+	definitionDiagram = iota
+	definitionJSON
+	definitionYAML
+)
+
+var definitionFormats = []string{"diagram", "JSON", "YAML"}
+
+// renderDefinition renders the pipeline declaration in the requested format
+func renderDefinition(width, format int, name string, c *uiData) (string, error) {
+	data := c.dataCache.pipelines[name].Data
+	if data == nil || data.Pipeline == nil {
+		return "", fmt.Errorf("%v definition not loaded", name)
+	}
+
+	switch format {
+	case definitionJSON, definitionYAML:
+		doc, err := pipeline.FromOutput(data)
+		if err != nil {
+			return "", err
+		}
+		if format == definitionJSON {
+			content, err := doc.JSON()
+			return renderCode(width, "json", content), err
+		}
+		content, err := doc.YAML()
+		return renderCode(width, "yaml", content), err
+	default:
+		return pipelineDiagram(data.Pipeline), nil
+	}
+}
+
+// pipelineDiagram renders the structure of a pipeline: stages, parallel actions, artifacts wiring and configuration
+func pipelineDiagram(declaration *types.PipelineDeclaration) string {
+	var b strings.Builder
+
+	label := lipgloss.NewStyle().Bold(true)
+	stageStyle := lipgloss.NewStyle().Bold(true).Foreground(tint.BrightBlue())
+	actionStyle := lipgloss.NewStyle().Foreground(tint.Green())
+	faint := lipgloss.NewStyle().Faint(true)
+
+	fmt.Fprintf(&b, "%v %v\n", label.Render("Pipeline:"), aws.ToString(declaration.Name))
+	if declaration.PipelineType != "" {
+		fmt.Fprintf(&b, "%v %v\n", label.Render("Type:"), declaration.PipelineType)
+	}
+	if declaration.ExecutionMode != "" {
+		fmt.Fprintf(&b, "%v %v\n", label.Render("Execution mode:"), declaration.ExecutionMode)
+	}
+	if declaration.Version != nil {
+		fmt.Fprintf(&b, "%v %v\n", label.Render("Version:"), *declaration.Version)
+	}
+	fmt.Fprintf(&b, "%v %v\n", label.Render("Role:"), aws.ToString(declaration.RoleArn))
+	if declaration.ArtifactStore != nil {
+		fmt.Fprintf(&b, "%v %v\n", label.Render("Artifact store:"), artifactStore(*declaration.ArtifactStore))
+	}
+	regions := make([]string, 0, len(declaration.ArtifactStores))
+	for region := range declaration.ArtifactStores {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	for _, region := range regions {
+		fmt.Fprintf(&b, "%v %v\n", label.Render("Artifact store "+region+":"), artifactStore(declaration.ArtifactStores[region]))
+	}
+	b.WriteString("\n")
+
+	// producers and consumers of each artifact, as Stage/Action
+	producers := map[string][]string{}
+	consumers := map[string][]string{}
+	var artifacts []string
+
+	for i, stage := range declaration.Stages {
+		fmt.Fprintf(&b, "%v\n", stageStyle.Render("■ "+aws.ToString(stage.Name)))
+		for _, blocker := range stage.Blockers {
+			fmt.Fprintf(&b, "│  %v\n", faint.Render(fmt.Sprintf("blocker: %v (%v)", aws.ToString(blocker.Name), blocker.Type)))
+		}
+
+		for _, group := range pipeline.RunOrderGroups(stage.Actions) {
+			for j, action := range group {
+				marker := runOrderMarker(j, len(group))
+				order := "   "
+				if j == 0 {
+					order = fmt.Sprintf("%-3v", pipeline.RunOrder(action))
+				}
+
+				id := fmt.Sprintf("%v/%v", aws.ToString(stage.Name), aws.ToString(action.Name))
+				fmt.Fprintf(&b, "│  %v %v %v  %v\n", order, marker, actionStyle.Render(aws.ToString(action.Name)), faint.Render(actionType(action)))
+
+				// Continue the parallel branch line below the action
+				indent := "│         "
+				if len(group) > 1 && j < len(group)-1 {
+					indent = "│      │  "
+				}
+
+				var in, out []string
+				for _, artifact := range action.InputArtifacts {
+					name := aws.ToString(artifact.Name)
+					in = append(in, name)
+					consumers[name] = append(consumers[name], id)
+				}
+				for _, artifact := range action.OutputArtifacts {
+					name := aws.ToString(artifact.Name)
+					out = append(out, name)
+					if _, ok := producers[name]; !ok {
+						artifacts = append(artifacts, name)
+					}
+					producers[name] = append(producers[name], id)
+				}
+				if len(in) > 0 || len(out) > 0 {
+					fmt.Fprintf(&b, "%v%v ⇒ %v\n", indent, artifactList(in), artifactList(out))
+				}
+				if action.Region != nil {
+					fmt.Fprintf(&b, "%v%v\n", indent, faint.Render("region: "+aws.ToString(action.Region)))
+				}
+				if action.Namespace != nil {
+					fmt.Fprintf(&b, "%v%v\n", indent, faint.Render("namespace: "+aws.ToString(action.Namespace)))
+				}
+
+				keys := make([]string, 0, len(action.Configuration))
+				for k := range action.Configuration {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					fmt.Fprintf(&b, "%v%v\n", indent, faint.Render(fmt.Sprintf("%v: %v", k, action.Configuration[k])))
+				}
+			}
+		}
+
+		if i < len(declaration.Stages)-1 {
+			b.WriteString("│\n▼\n")
+		}
+	}
+
+	if len(artifacts) > 0 {
+		fmt.Fprintf(&b, "\n%v\n", label.Render("Artifacts:"))
+		for _, name := range artifacts {
+			fmt.Fprintf(&b, "  %v: %v ⇒ %v\n", name, artifactList(producers[name]), artifactList(consumers[name]))
+		}
+	}
+	return b.String()
+}
+
+// runOrderMarker returns the branch of the action idx of a run order group of size actions
+func runOrderMarker(idx, size int) string {
+	switch {
+	case size == 1:
+		return "──"
+	case idx == 0:
+		return "┬─"
+	case idx == size-1:
+		return "└─"
+	default:
+		return "├─"
+	}
+}
+
+func actionType(action types.ActionDeclaration) string {
+	if action.ActionTypeId == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v/%v", action.ActionTypeId.Category, aws.ToString(action.ActionTypeId.Provider))
+}
+
+func artifactStore(store types.ArtifactStore) string {
+	s := fmt.Sprintf("%v %v", store.Type, aws.ToString(store.Location))
+	if store.EncryptionKey != nil {
+		s += fmt.Sprintf(" (%v %v)", store.EncryptionKey.Type, aws.ToString(store.EncryptionKey.Id))
+	}
+	return s
+}
+
+func artifactList(names []string) string {
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, ", ")
+}
+
+// diffPipeline compares the definition of a pipeline with another pipeline, version or local file and displays the changes
+func (c *uiData) diffPipeline(name, from string) {
+	c.startSpinner()
+	defer c.stopSpinner()
+
+	get := func(name string, version int32) (*codepipeline.GetPipelineOutput, error) {
+		return awsqueries.GetPipelineVersion(config.AwsConfig, name, version)
+	}
+	fromSource, err := pipeline.ParseSource(expandHome(from))
+	if err != nil {
+		c.errorMsg(diffView, err.Error())
+		return
+	}
+	fromDoc, err := fromSource.Document(get)
+	if err != nil {
+		c.errorMsg(diffView, fmt.Sprintf("failed to load %v: %v", fromSource, err))
+		return
+	}
+	toDoc, err := pipeline.Source{Name: name}.Document(get)
+	if err != nil {
+		c.errorMsg(diffView, fmt.Sprintf("failed to load %v: %v", name, err))
+		return
+	}
+
+	changes := pipeline.Diff(fromDoc, toDoc)
+	log.Debug().Str("model", "tui").Str("func", "diffPipeline").Msgf("%v => %v: %d changes", fromSource, name, len(changes))
+	c.changeView(pipelineView, diffView, PagerSelector{
+		name:    fmt.Sprintf("%v => %v", fromSource, name),
+		content: renderDiff(fromSource.String(), name, changes),
+	})
+}
+
+// renderDiff renders the changes between two pipelines definitions
+func renderDiff(from, to string, changes []pipeline.Change) string {
+	var b strings.Builder
+
+	styles := map[string]lipgloss.Style{
+		pipeline.Added:    lipgloss.NewStyle().Foreground(tint.Green()),
+		pipeline.Removed:  lipgloss.NewStyle().Foreground(tint.Red()),
+		pipeline.Modified: lipgloss.NewStyle().Foreground(tint.Yellow()),
+	}
+	fmt.Fprintf(&b, "%v\n%v\n\n", styles[pipeline.Removed].Render("--- "+from), styles[pipeline.Added].Render("+++ "+to))
+	if len(changes) == 0 {
+		b.WriteString("No differences\n")
+	}
+	for _, change := range changes {
+		fmt.Fprintf(&b, "%v\n", styles[change.Kind].Render(change.String()))
+	}
+	return b.String()
+}
@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+	"github.com/fabio42/codeplumber/events"
+
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline/types"
+	"github.com/rs/zerolog/log"
+)
+
+// pipelineEvent is an event of the configured source, applied to the data cache on the Update loop
+// described is the pipeline of a new execution, described in the background before the event is applied
+type pipelineEvent struct {
+	event     events.Event
+	described *awsqueries.Pipeline
+}
+
+// listenEvents sends the events of the configured source to the Update loop
+func (c *uiData) listenEvents(source events.Source) {
+	ch := make(chan events.Event)
+	go func() {
+		if err := source.Run(context.Background(), ch); err != nil {
+			log.Debug().Str("model", "tui").Str("func", "listenEvents").Msgf("event source error: %v", err)
+			c.errorMsg(c.currentView(), fmt.Sprintf("event source stopped, press %v to refresh manually: %v", allKeys.Refresh.Help().Key, err))
+		}
+	}()
+
+	for e := range ch {
+		log.Debug().Str("model", "tui").Str("func", "listenEvents").Msgf("event: %+v", e)
+		if (e.Account != "" && e.Account != config.awsAccountID) || (e.Region != "" && e.Region != config.AwsConfig.Region) {
+			continue
+		}
+		c.sendEvent(pipelineEvent{event: e})
+	}
+}
+
+func (c *uiData) sendEvent(event pipelineEvent) {
+	c.selection <- tuiMsg{
+		class: sourceMsg,
+		data:  event,
+	}
+}
+
+// applyEvent updates the data cache, the pipelines listing and notifies the views showing the pipeline or build
+// It runs on the Update loop, messages are sent from goroutines
+func (c *uiData) applyEvent(pe pipelineEvent) {
+	e := pe.event
+	if !e.IsPipeline() {
+		go c.notifyEvent(codebuildView, e.BuildExecutionID())
+		return
+	}
+
+	switch {
+	case e.DetailType == events.PipelineExecutionChange && c.initialized:
+		pipeline, ok := c.dataCache.pipelines[e.Pipeline]
+		if !ok {
+			break
+		}
+		switch {
+		case pe.described != nil:
+			// The definition and the state are only described by the pipeline view
+			described := *pe.described
+			described.Data = pipeline.Data
+			described.StateData = pipeline.StateData
+			pipeline = described
+		case knownExecution(pipeline, e):
+			pipeline = applyExecutionEvent(pipeline, e)
+		default:
+			// The event is applied again once the new execution is described
+			go c.describeEvent(pipeline.PipelineName, pipeline.Tags, e)
+			return
+		}
+		c.dataCache.pipelines[e.Pipeline] = pipeline
+		rows, _ := pipelinesTableRows(c.dataCache.pipelines)
+		go c.updateView(pipelinesView, pipelinesRows{rows: rows, stale: c.revalidating})
+		go c.observe(pipeline)
+	case e.DetailType == events.ActionExecutionChange && e.Category == string(types.ActionCategoryApproval) && e.State == "STARTED":
+		go c.observeApproval(e)
+	}
+	go c.notifyEvent(pipelineView, e.Pipeline)
+	go c.notifyEvent(dashboardView, e.Pipeline)
+}
+
+// knownExecution returns true when the event is about the last execution of the pipeline already described
+func knownExecution(pipeline awsqueries.Pipeline, e events.Event) bool {
+	return pipeline.LastExecutionID == e.ExecutionID && pipeline.ExecData != nil && len(pipeline.ExecData.PipelineExecutionSummaries) > 0
+}
+
+// applyExecutionEvent updates the state of the known last execution of a pipeline in place
+func applyExecutionEvent(pipeline awsqueries.Pipeline, e events.Event) awsqueries.Pipeline {
+	status := events.PipelineStatus(e.State)
+	summary := &pipeline.ExecData.PipelineExecutionSummaries[0]
+	summary.Status = types.PipelineExecutionStatus(status)
+	summary.LastUpdateTime = &e.Time
+	pipeline.LastExecutionStatus = status
+	if summary.Status == types.PipelineExecutionStatusSucceeded && describeOptions(tableColumns()).LastSuccess {
+		success := *summary
+		pipeline.LastSuccess = &success
+	}
+	return pipeline
+}
+
+// describeEvent describes the pipeline of a new execution with a single query and sends the event back to the Update loop
+func (c *uiData) describeEvent(name string, tags map[string]string, e events.Event) {
+	described := awsqueries.DescribePipeline(config.AwsConfig, config.awsAccountID, name, tags, describeOptions(tableColumns()))
+	if described.Error != "" {
+		log.Debug().Str("model", "tui").Str("func", "describeEvent").Msgf("failed to describe %v: %v", name, described.Error)
+		return
+	}
+	c.sendEvent(pipelineEvent{event: e, described: &described})
+}
+
+// notifyEvent notifies a view that the pipeline or build it shows changed
+func (c *uiData) notifyEvent(view, name string) {
+	c.selection <- tuiMsg{
+		class: eventMsg,
+		id:    view,
+		data:  name,
+	}
+}
+
+// poll periodically refreshes the active view, it is used when no event source is configured
+func (c *uiData) poll(interval time.Duration) {
+	for range time.Tick(interval) {
+		c.selection <- tuiMsg{
+			class: pollMsg,
+		}
+	}
+}
+
+// isOpen returns true when a view is displayed or in the navigation history
+func (c *uiData) isOpen(view string) bool {
+	return slices.Contains(c.views[:c.viewIdx+1], view)
+}
@@ -0,0 +1,453 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+	"github.com/fabio42/codeplumber/models/table"
+)
+
+// filterQuery is a filter of the pipelines listing
+//
+// A query is a list of terms, all of them must match unless separated by OR:
+//
+//	status:Failed updated:<1d -tag:team=platform
+//	(status:Failed OR status:Stopped) name:~^svc-.*-prod$
+//
+// Terms are scoped to a field (field:value) or match any cell of the row, values are matched
+// case-insensitively: value is a substring, =value the whole value and ~value a regular expression.
+// Times and durations are compared with < and >: updated:<2h, updated:>2024-01-31, duration:>10m.
+// A term is negated with a leading - or NOT, terms are grouped with parentheses.
+type filterQuery interface {
+	match(p awsqueries.Pipeline, row table.Row) bool
+}
+
+type andQuery []filterQuery
+
+func (q andQuery) match(p awsqueries.Pipeline, row table.Row) bool {
+	for _, term := range q {
+		if !term.match(p, row) {
+			return false
+		}
+	}
+	return true
+}
+
+type orQuery []filterQuery
+
+func (q orQuery) match(p awsqueries.Pipeline, row table.Row) bool {
+	for _, term := range q {
+		if term.match(p, row) {
+			return true
+		}
+	}
+	return false
+}
+
+type notQuery struct {
+	filterQuery
+}
+
+func (q notQuery) match(p awsqueries.Pipeline, row table.Row) bool {
+	return !q.filterQuery.match(p, row)
+}
+
+// anyQuery matches a value in any cell of the row
+type anyQuery struct {
+	value valueMatcher
+}
+
+func (q anyQuery) match(_ awsqueries.Pipeline, row table.Row) bool {
+	return slices.ContainsFunc(row, q.value.match)
+}
+
+// fieldQuery matches the value of a field
+type fieldQuery struct {
+	value      func(p awsqueries.Pipeline) string
+	matchValue valueMatcher
+}
+
+func (q fieldQuery) match(p awsqueries.Pipeline, _ table.Row) bool {
+	return q.matchValue.match(q.value(p))
+}
+
+// tagQuery matches the tags, any value matches when value is nil
+type tagQuery struct {
+	key   string
+	value *valueMatcher
+}
+
+func (q tagQuery) match(p awsqueries.Pipeline, _ table.Row) bool {
+	value, ok := p.Tags[q.key]
+	if !ok {
+		return false
+	}
+	return q.value == nil || q.value.match(value)
+}
+
+// timeQuery compares a time with a date, or its age with a duration
+type timeQuery struct {
+	value  func(p awsqueries.Pipeline) *time.Time
+	before bool
+	age    time.Duration
+	date   time.Time
+}
+
+func (q timeQuery) match(p awsqueries.Pipeline, _ table.Row) bool {
+	t := q.value(p)
+	if t == nil {
+		return false
+	}
+	if q.age > 0 {
+		// updated:<2h is an age below 2 hours
+		if q.before {
+			return time.Since(*t) < q.age
+		}
+		return time.Since(*t) > q.age
+	}
+	if q.before {
+		return t.Before(q.date)
+	}
+	return t.After(q.date)
+}
+
+// durationQuery compares the duration of the last execution
+type durationQuery struct {
+	shorter  bool
+	duration time.Duration
+}
+
+func (q durationQuery) match(p awsqueries.Pipeline, _ table.Row) bool {
+	d, ok := executionDuration(p)
+	if !ok {
+		return false
+	}
+	if q.shorter {
+		return d < q.duration
+	}
+	return d > q.duration
+}
+
+// valueMatcher matches a substring, a whole value or a regular expression, case-insensitively
+type valueMatcher struct {
+	value string
+	exact bool
+	re    *regexp.Regexp
+}
+
+func newValueMatcher(s string) (valueMatcher, error) {
+	switch {
+	case strings.HasPrefix(s, "~"):
+		re, err := regexp.Compile("(?i)" + s[1:])
+		if err != nil {
+			return valueMatcher{}, fmt.Errorf("invalid regular expression %q: %w", s[1:], err)
+		}
+		return valueMatcher{re: re}, nil
+	case strings.HasPrefix(s, "="):
+		return valueMatcher{value: strings.ToLower(s[1:]), exact: true}, nil
+	default:
+		return valueMatcher{value: strings.ToLower(s)}, nil
+	}
+}
+
+func (m valueMatcher) match(s string) bool {
+	switch {
+	case m.re != nil:
+		return m.re.MatchString(s)
+	case m.exact:
+		return strings.ToLower(s) == m.value
+	default:
+		return strings.Contains(strings.ToLower(s), m.value)
+	}
+}
+
+// filterFields are the fields matched by value, the other fields are tag, duration and the times
+var filterFields = map[string]string{
+	"name":    "name",
+	"status":  "status",
+	"user":    "user",
+	"changes": "changes",
+	"stage":   "stage",
+	"account": "account",
+	"region":  "region",
+}
+
+// filterTimes are the time fields
+var filterTimes = map[string]func(p awsqueries.Pipeline) *time.Time{
+	"updated": func(p awsqueries.Pipeline) *time.Time {
+		if summary := lastExecution(p); summary != nil {
+			return summary.LastUpdateTime
+		}
+		return nil
+	},
+	"started": func(p awsqueries.Pipeline) *time.Time {
+		if summary := lastExecution(p); summary != nil {
+			return summary.StartTime
+		}
+		return nil
+	},
+	"succeeded": func(p awsqueries.Pipeline) *time.Time {
+		if p.LastSuccess != nil {
+			return p.LastSuccess.LastUpdateTime
+		}
+		return nil
+	},
+}
+
+// parseFilterQuery parses a filter query, an empty query matches all the pipelines
+func parseFilterQuery(s string) (filterQuery, error) {
+	tokens, err := filterTokens(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	if len(tokens) == 0 {
+		return andQuery{}, nil
+	}
+
+	q, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return q, nil
+}
+
+// filterTokens splits a query in terms and parentheses, double quotes group words
+func filterTokens(s string) ([]string, error) {
+	var tokens []string
+	var token strings.Builder
+	quoted := false
+	flush := func() {
+		if token.Len() > 0 {
+			tokens = append(tokens, token.String())
+			token.Reset()
+		}
+	}
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+			token.WriteRune(r)
+		case unicode.IsSpace(r):
+			flush()
+		case (r == '(' || r == ')') && token.Len() == 0:
+			tokens = append(tokens, string(r))
+		case r == ')':
+			// Closing parenthesis of a group, regular expressions keep their balanced parentheses
+			if strings.Count(token.String(), "(") > strings.Count(token.String(), ")") {
+				token.WriteRune(r)
+			} else {
+				flush()
+				tokens = append(tokens, string(r))
+			}
+		default:
+			token.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	flush()
+	return tokens, nil
+}
+
+type queryParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *queryParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// or parses terms separated by OR
+func (p *queryParser) or() (filterQuery, error) {
+	var terms orQuery
+	for {
+		q, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, q)
+		if t := p.peek(); t != "OR" && t != "|" {
+			break
+		}
+		p.pos++
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+// and parses consecutive terms
+func (p *queryParser) and() (filterQuery, error) {
+	var terms andQuery
+	for {
+		switch p.peek() {
+		case "", ")", "OR", "|":
+			if len(terms) == 0 {
+				return nil, fmt.Errorf("missing term")
+			}
+			if len(terms) == 1 {
+				return terms[0], nil
+			}
+			return terms, nil
+		case "AND":
+			p.pos++
+			continue
+		}
+		q, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, q)
+	}
+}
+
+// unary parses a negated term, a group or a term
+func (p *queryParser) unary() (filterQuery, error) {
+	t := p.peek()
+	p.pos++
+	switch {
+	case t == "" || t == ")" || t == "OR" || t == "|" || t == "AND":
+		return nil, fmt.Errorf("missing term")
+	case t == "NOT":
+		q, err := p.unary()
+		return notQuery{q}, err
+	case t == "(":
+		q, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return q, nil
+	case len(t) > 1 && (t[0] == '-' || t[0] == '!'):
+		q, err := parseFilterTerm(t[1:])
+		return notQuery{q}, err
+	default:
+		return parseFilterTerm(t)
+	}
+}
+
+// parseFilterTerm parses a field:value term, or a value matched in any cell of the row
+func parseFilterTerm(t string) (filterQuery, error) {
+	field, value, found := strings.Cut(t, ":")
+	if !found {
+		m, err := newValueMatcher(t)
+		return anyQuery{m}, err
+	}
+	field = strings.ToLower(field)
+
+	if column, ok := filterFields[field]; ok {
+		m, err := newValueMatcher(value)
+		return fieldQuery{
+			value:      func(p awsqueries.Pipeline) string { return pipelinesColumns[column].value(p, Column{}) },
+			matchValue: m,
+		}, err
+	}
+
+	if get, ok := filterTimes[field]; ok {
+		before, operand, err := comparison(field, value)
+		if err != nil {
+			return nil, err
+		}
+		if age, err := parseAge(operand); err == nil {
+			return timeQuery{value: get, before: before, age: age}, nil
+		}
+		for _, layout := range []string{"2006-01-02", "2006-01-02T15:04", time.RFC3339} {
+			if date, err := time.ParseInLocation(layout, operand, time.Local); err == nil {
+				return timeQuery{value: get, before: before, date: date}, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid %v: %q is neither a duration (2h, 1d) nor a date (2006-01-02)", field, operand)
+	}
+
+	switch field {
+	case "tag":
+		key, tagValue, hasValue := strings.Cut(value, "=")
+		if key == "" {
+			return nil, fmt.Errorf("missing tag name")
+		}
+		if !hasValue {
+			return tagQuery{key: key}, nil
+		}
+		// Tag values are matched as a whole unless a regular expression is used
+		if !strings.HasPrefix(tagValue, "~") {
+			tagValue = "=" + tagValue
+		}
+		m, err := newValueMatcher(tagValue)
+		return tagQuery{key: key, value: &m}, err
+
+	case "duration":
+		shorter, operand, err := comparison(field, value)
+		if err != nil {
+			return nil, err
+		}
+		d, err := parseAge(operand)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q", operand)
+		}
+		return durationQuery{shorter: shorter, duration: d}, nil
+	}
+
+	fields := []string{"tag", "duration"}
+	for f := range filterFields {
+		fields = append(fields, f)
+	}
+	for f := range filterTimes {
+		fields = append(fields, f)
+	}
+	slices.Sort(fields)
+	return nil, fmt.Errorf("unknown field %q, supported fields: %v", field, strings.Join(fields, ", "))
+}
+
+// comparison returns true for <, false for >, and the operand
+func comparison(field, value string) (bool, string, error) {
+	switch {
+	case strings.HasPrefix(value, "<"):
+		return true, value[1:], nil
+	case strings.HasPrefix(value, ">"):
+		return false, value[1:], nil
+	default:
+		return false, "", fmt.Errorf("%v requires < or >, e.g. %v:<2h", field, field)
+	}
+}
+
+// parseAge parses a duration, days (d) and weeks (w) are supported
+func parseAge(s string) (time.Duration, error) {
+	for unit, d := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, found := strings.CutSuffix(s, unit); found {
+			v, err := strconv.ParseFloat(n, 64)
+			if err != nil {
+				return 0, err
+			}
+			if v <= 0 {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(v * float64(d)), nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err == nil && d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, err
+}
@@ -0,0 +1,154 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+	"github.com/fabio42/codeplumber/models/table"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline"
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline/types"
+)
+
+// testPipeline returns a pipeline whose last execution has a status and was updated age ago
+func testPipeline(name string, status types.PipelineExecutionStatus, age time.Duration, tags map[string]string) awsqueries.Pipeline {
+	updated := time.Now().Add(-age)
+	return awsqueries.Pipeline{
+		PipelineName:        name,
+		LastExecutionID:     name + "-execution",
+		LastExecutionStatus: string(status),
+		ExecData: &codepipeline.ListPipelineExecutionsOutput{
+			PipelineExecutionSummaries: []types.PipelineExecutionSummary{{
+				PipelineExecutionId: aws.String(name + "-execution"),
+				Status:              status,
+				StartTime:           &updated,
+				LastUpdateTime:      &updated,
+			}},
+		},
+		Tags: tags,
+	}
+}
+
+func TestFilterQueryMatch(t *testing.T) {
+	pipelines := []awsqueries.Pipeline{
+		testPipeline("svc-api-prod", types.PipelineExecutionStatusFailed, time.Hour, map[string]string{"team": "platform"}),
+		testPipeline("svc-api-dev", types.PipelineExecutionStatusSucceeded, 3*time.Hour, map[string]string{"team": "web"}),
+		testPipeline("svc-web-prod", types.PipelineExecutionStatusStopped, 30*time.Minute, nil),
+		testPipeline("batch-prod", types.PipelineExecutionStatusSucceeded, 48*time.Hour, map[string]string{"team": "platform"}),
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"svc-api-prod", "svc-api-dev", "svc-web-prod", "batch-prod"}},
+		{"api", []string{"svc-api-prod", "svc-api-dev"}},
+		{"name:=batch-prod", []string{"batch-prod"}},
+		{"name:=batch", nil},
+		{"STATUS:failed", []string{"svc-api-prod"}},
+		{"name:~^svc-.*-prod$", []string{"svc-api-prod", "svc-web-prod"}},
+		{"~^BATCH", []string{"batch-prod"}},
+		{"-name:svc", []string{"batch-prod"}},
+		{"!name:svc", []string{"batch-prod"}},
+		{"NOT name:svc", []string{"batch-prod"}},
+		{"NOT (name:api OR name:batch)", []string{"svc-web-prod"}},
+		{"-tag:team", []string{"svc-web-prod"}},
+		{"tag:team=platform", []string{"svc-api-prod", "batch-prod"}},
+		{"tag:team=plat", nil},
+		{"tag:team=~^plat", []string{"svc-api-prod", "batch-prod"}},
+		{"updated:<2h", []string{"svc-api-prod", "svc-web-prod"}},
+		{"updated:>1d", []string{"batch-prod"}},
+		{"duration:>1m", nil},
+		// AND binds tighter than OR
+		{"status:Failed OR status:Stopped name:web", []string{"svc-api-prod", "svc-web-prod"}},
+		{"status:Succeeded name:api OR name:batch", []string{"svc-api-dev", "batch-prod"}},
+		{"(status:Failed OR status:Stopped) name:web", []string{"svc-web-prod"}},
+		{"status:Succeeded AND name:batch", []string{"batch-prod"}},
+		{"status:Succeeded | status:Failed -name:dev", []string{"svc-api-prod", "svc-api-dev", "batch-prod"}},
+		// Regular expressions keep their parentheses
+		{"name:~(api|web)-prod", []string{"svc-api-prod", "svc-web-prod"}},
+		{`"svc-web-prod"`, []string{"svc-web-prod"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := parseFilterQuery(tt.query)
+			if err != nil {
+				t.Fatalf("parseFilterQuery(%q): %v", tt.query, err)
+			}
+			var got []string
+			for _, p := range pipelines {
+				if q.match(p, table.Row{p.PipelineName, p.LastExecutionStatus}) {
+					got = append(got, p.PipelineName)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("query %q matched %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterQueryErrors(t *testing.T) {
+	tests := []string{
+		"(status:Failed",
+		"status:Failed)",
+		"OR status:Failed",
+		"status:Failed OR",
+		"NOT",
+		"name:svc NOT",
+		"NOT OR name:svc",
+		"()",
+		`"unterminated`,
+		"name:~[a-",
+		"unknown:value",
+		"updated:2h",
+		"updated:<soon",
+		"duration:>0s",
+		"updated:<0d",
+		"updated:>-1d",
+		"duration:<0w",
+		"tag:=platform",
+	}
+	for _, query := range tests {
+		t.Run(query, func(t *testing.T) {
+			if _, err := parseFilterQuery(query); err == nil {
+				t.Errorf("parseFilterQuery(%q) succeeded, want an error", query)
+			}
+		})
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		err   bool
+	}{
+		{"2h", 2 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"1d", 24 * time.Hour, false},
+		{"1.5d", 36 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"0s", 0, true},
+		{"0d", 0, true},
+		{"-1d", 0, true},
+		{"0w", 0, true},
+		{"-1h", 0, true},
+		{"xd", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseAge(tt.value)
+			if (err != nil) != tt.err {
+				t.Fatalf("parseAge(%q) error = %v, want error %v", tt.value, err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("parseAge(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -53,3 +53,11 @@ func supportedView(s string) bool {
 	}
 	return false
 }
+
+// printOptionalTime return PrintTime output or N/A when the time is not set
+func printOptionalTime(t *time.Time) string {
+	if t == nil {
+		return "N/A"
+	}
+	return PrintTime(t)
+}
@@ -1,6 +1,11 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"github.com/fabio42/codeplumber/models/table"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+)
 
 type keyMap struct {
 	Up               key.Binding
@@ -21,6 +26,29 @@ type keyMap struct {
 	ReStart          key.Binding
 	Confirm          key.Binding
 	Decline          key.Binding
+	Approve          key.Binding
+	Reject           key.Binding
+	ChangeSet        key.Binding
+	Artifacts        key.Binding
+	Download         key.Binding
+	Extract          key.Binding
+	Definition       key.Binding
+	Format           key.Binding
+	Diff             key.Binding
+	Execution        key.Binding
+	Variables        key.Binding
+	Sort             key.Binding
+	SortReverse      key.Binding
+	Pin              key.Binding
+	SaveView         key.Binding
+	NextView         key.Binding
+	DeleteView       key.Binding
+	Dashboard        key.Binding
+	Split            key.Binding
+	NextPane         key.Binding
+	PrevPane         key.Binding
+	Palette          key.Binding
+	Cancel           key.Binding
 }
 
 var allKeys = keyMap{
@@ -34,13 +62,17 @@ var allKeys = keyMap{
 	Filter:   key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "filter")),
 	Search:   key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
 	Browse:   key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "open in browser")),
-	NextTint: key.NewBinding(key.WithKeys("]"), key.WithHelp("t", "next tint")),
-	PrevTint: key.NewBinding(key.WithKeys("["), key.WithHelp("T", "previous tint")),
+	NextTint: key.NewBinding(key.WithKeys("]"), key.WithHelp("]", "next tint")),
+	PrevTint: key.NewBinding(key.WithKeys("["), key.WithHelp("[", "previous tint")),
 	Start:    key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "start")),
+	Split:    key.NewBinding(key.WithKeys("|"), key.WithHelp("|", "toggle split layout")),
+	NextPane: key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next pane")),
+	PrevPane: key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "previous pane")),
+	Palette:  key.NewBinding(key.WithKeys(":", "ctrl+p"), key.WithHelp(":/ctrl+p", "command palette")),
 }
 
 var codebuildKeys = keyMap{
-	Log:   key.NewBinding(key.WithKeys("l"), key.WithHelp("l", "log")),
+	Log:   key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "log")),
 	Start: key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "restart failed CodeBuild")),
 }
 
@@ -48,12 +80,62 @@ var codePipelineKeys = keyMap{
 	Start:            key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "start CodePipeline")),
 	ReStart:          key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "restart failed stage")),
 	ToggleTransition: key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "toggle transition")),
+	Approve:          key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "approve")),
+	Reject:           key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "reject")),
+	ChangeSet:        key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "review change set")),
+	Artifacts:        key.NewBinding(key.WithKeys("A"), key.WithHelp("A", "artifacts")),
+	Definition:       key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "definition")),
+	Diff:             key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "diff definition")),
+	Execution:        key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "select execution")),
+	Variables:        key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "toggle action variables")),
+}
+
+var pipelinesKeys = keyMap{
+	Sort:        key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "sort by next column")),
+	SortReverse: key.NewBinding(key.WithKeys("O"), key.WithHelp("O", "reverse sort")),
+	Pin:         key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pin/unpin")),
+	SaveView:    key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "save view")),
+	NextView:    key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "next saved view")),
+	DeleteView:  key.NewBinding(key.WithKeys("W"), key.WithHelp("W", "delete view")),
+	Dashboard:   key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "dashboard")),
+}
+
+var artifactKeys = keyMap{
+	Download: key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "download artifact")),
+	Extract:  key.NewBinding(key.WithKeys("X"), key.WithHelp("X", "extract artifact")),
 }
 
 var pagerKeys = keyMap{
+	Up:      key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "previous query")),
+	Down:    key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "next query")),
 	Select:  key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
+	Cancel:  key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
 	Confirm: key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes")),
 	Decline: key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "no")),
+	Format:  key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "diagram/JSON/YAML")),
+}
+
+// tableKeys are the navigation keys of the tables, the lines are moved with the up and down keys
+// Unlike the default keymap of the tables, b, f, d and u are left to the commands of the views
+var tableKeys = table.KeyMap{
+	LineUp:       allKeys.Up,
+	LineDown:     allKeys.Down,
+	PageUp:       key.NewBinding(key.WithKeys("pgup"), key.WithHelp("pgup", "page up")),
+	PageDown:     key.NewBinding(key.WithKeys("pgdown", " "), key.WithHelp("pgdn/space", "page down")),
+	HalfPageUp:   key.NewBinding(key.WithKeys("ctrl+u"), key.WithHelp("ctrl+u", "½ page up")),
+	HalfPageDown: key.NewBinding(key.WithKeys("ctrl+d"), key.WithHelp("ctrl+d", "½ page down")),
+	GotoTop:      key.NewBinding(key.WithKeys("home", "g"), key.WithHelp("g/home", "go to start")),
+	GotoBottom:   key.NewBinding(key.WithKeys("end", "G"), key.WithHelp("G/end", "go to end")),
+}
+
+// viewportKeys are the scroll keys of the pager, less-like, the lines are scrolled with the up and down keys
+var viewportKeys = viewport.KeyMap{
+	Up:           allKeys.Up,
+	Down:         allKeys.Down,
+	PageUp:       key.NewBinding(key.WithKeys("pgup", "b"), key.WithHelp("b/pgup", "page up")),
+	PageDown:     key.NewBinding(key.WithKeys("pgdown", " ", "f"), key.WithHelp("f/pgdn", "page down")),
+	HalfPageUp:   key.NewBinding(key.WithKeys("u", "ctrl+u"), key.WithHelp("u", "½ page up")),
+	HalfPageDown: key.NewBinding(key.WithKeys("d", "ctrl+d"), key.WithHelp("d", "½ page down")),
 }
 
 var helpLeft = []key.Binding{
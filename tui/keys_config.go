@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/rs/zerolog/log"
+)
+
+// keyContexts are the keymaps which can be configured, by name in the configuration file
+func keyContexts() map[string]map[string]*key.Binding {
+	contexts := map[string]map[string]*key.Binding{
+		"global":    bindings(&allKeys),
+		"pipelines": bindings(&pipelinesKeys),
+		"pipeline":  bindings(&codePipelineKeys),
+		"codebuild": bindings(&codebuildKeys),
+		"artifacts": bindings(&artifactKeys),
+		"pager":     bindings(&pagerKeys),
+		"table":     bindings(&tableKeys),
+		"viewport":  bindings(&viewportKeys),
+	}
+	// Lines are moved with the global up and down keys
+	delete(contexts["table"], "lineUp")
+	delete(contexts["table"], "lineDown")
+	delete(contexts["viewport"], "up")
+	delete(contexts["viewport"], "down")
+	return contexts
+}
+
+// bindings returns the bindings of a keymap by name, the name of the field starting with a lowercase letter
+// Fields without keys are not used in the context of the keymap
+func bindings(keymap interface{}) map[string]*key.Binding {
+	v := reflect.ValueOf(keymap).Elem()
+	b := make(map[string]*key.Binding, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		binding, ok := v.Field(i).Addr().Interface().(*key.Binding)
+		if !ok || len(binding.Keys()) == 0 {
+			continue
+		}
+		name := v.Type().Field(i).Name
+		b[strings.ToLower(name[:1])+name[1:]] = binding
+	}
+	return b
+}
+
+var (
+	// modelKeys are handled before the views, in all the views
+	modelKeys = []string{"global.quit", "global.help", "global.nextTint", "global.prevTint", "global.palette", "global.split", "global.nextPane", "global.prevPane"}
+	// tableNavigationKeys are handled by the tables after the views
+	tableNavigationKeys = []string{"global.up", "global.down", "table.pageUp", "table.pageDown", "table.halfPageUp", "table.halfPageDown", "table.gotoTop", "table.gotoBottom"}
+	// viewportNavigationKeys are handled by the pager after the view
+	viewportNavigationKeys = []string{"global.up", "global.down", "viewport.pageUp", "viewport.pageDown", "viewport.halfPageUp", "viewport.halfPageDown"}
+)
+
+// keyScopes are the bindings handled together by a view, a key can only be bound once in a scope
+var keyScopes = []struct {
+	name     string
+	bindings [][]string
+}{
+	{pipelinesView, [][]string{modelKeys, tableNavigationKeys, {
+		"global.select", "global.refresh", "global.browse", "global.search", "pipeline.start",
+		"pipelines.sort", "pipelines.sortReverse", "pipelines.pin", "pipelines.saveView", "pipelines.nextView", "pipelines.deleteView", "pipelines.dashboard",
+	}}},
+	{pipelineView, [][]string{modelKeys, tableNavigationKeys, {
+		"global.select", "global.previous", "global.refresh", "global.browse",
+		"pipeline.start", "pipeline.reStart", "pipeline.toggleTransition", "pipeline.approve", "pipeline.reject", "pipeline.changeSet",
+		"pipeline.artifacts", "pipeline.definition", "pipeline.diff", "pipeline.execution", "pipeline.variables",
+	}}},
+	{codebuildView, [][]string{modelKeys, tableNavigationKeys, {"global.select", "global.previous", "global.refresh", "global.browse", "codebuild.log"}}},
+	{actionView, [][]string{modelKeys, tableNavigationKeys, {"global.previous", "global.refresh", "global.browse"}}},
+	{changesetView, [][]string{modelKeys, tableNavigationKeys, {"global.previous", "global.refresh", "pipeline.approve", "pipeline.reject"}}},
+	{artifactsView, [][]string{modelKeys, tableNavigationKeys, {"global.select", "global.previous", "global.refresh", "artifacts.download", "artifacts.extract"}}},
+	{archiveView, [][]string{modelKeys, tableNavigationKeys, {"global.previous", "global.refresh", "artifacts.download", "artifacts.extract"}}},
+	{dashboardView, [][]string{modelKeys, {"global.up", "global.down", "global.select", "global.previous", "global.refresh"}}},
+	{"pager", [][]string{modelKeys, viewportNavigationKeys, {"global.previous", "global.refresh", "pager.format"}}},
+	// The other keys are typed in the status line
+	{"input", [][]string{{"pager.select", "pager.cancel", "pager.up", "pager.down"}, {"pager.confirm", "pager.decline"}}},
+}
+
+// ConfigureKeys overrides the default keys, by context and binding name, and validates the conflicts between the keys
+// An empty list of keys disables a binding
+func ConfigureKeys(keys map[string]map[string][]string) error {
+	contexts := keyContexts()
+	for context, overrides := range keys {
+		bindings, ok := contexts[context]
+		if !ok {
+			return fmt.Errorf("unknown keys context %v, supported contexts: %v", context, strings.Join(sortedKeys(contexts), ", "))
+		}
+		for name, keys := range overrides {
+			binding, ok := bindings[name]
+			if !ok {
+				return fmt.Errorf("unknown key %v.%v, supported keys: %v", context, name, strings.Join(sortedKeys(bindings), ", "))
+			}
+			log.Debug().Str("model", "tui").Str("func", "ConfigureKeys").Msgf("%v.%v: %v", context, name, keys)
+			if len(keys) == 0 {
+				binding.SetEnabled(false)
+				continue
+			}
+			binding.SetKeys(keys...)
+			binding.SetHelp(helpKeys(keys), binding.Help().Desc)
+		}
+	}
+
+	// The lines of the tables and of the pager follow the global up and down keys
+	tableKeys.LineUp, tableKeys.LineDown = allKeys.Up, allKeys.Down
+	viewportKeys.Up, viewportKeys.Down = allKeys.Up, allKeys.Down
+
+	return validateKeys(contexts)
+}
+
+// validateKeys returns the keys bound to several bindings of a scope
+func validateKeys(contexts map[string]map[string]*key.Binding) error {
+	conflicts := []string{}
+	for _, scope := range keyScopes {
+		bound := map[string]string{}
+		for _, names := range scope.bindings {
+			for _, name := range names {
+				context, binding, _ := strings.Cut(name, ".")
+				b := contexts[context][binding]
+				if b == nil || !b.Enabled() {
+					continue
+				}
+				for _, k := range b.Keys() {
+					if other, ok := bound[k]; ok && other != name {
+						conflicts = append(conflicts, fmt.Sprintf("%v view: %q is bound to %v and %v", scope.name, k, other, name))
+						continue
+					}
+					bound[k] = name
+				}
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("conflicting keys:\n  %v", strings.Join(conflicts, "\n  "))
+	}
+	return nil
+}
+
+// helpKeys returns the keys displayed in the help, arrows are displayed as symbols
+func helpKeys(keys []string) string {
+	symbols := map[string]string{"up": "↑", "down": "↓", "left": "←", "right": "→", " ": "space"}
+	help := make([]string, len(keys))
+	for i, k := range keys {
+		help[i] = k
+		if symbol, ok := symbols[k]; ok {
+			help[i] = symbol
+		}
+	}
+	return strings.Join(help, "/")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
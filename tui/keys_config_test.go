@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+// restoreKeys restores the default keymaps once a test configured the keys
+func restoreKeys(t *testing.T) {
+	global, pipelines, pipeline, codebuild := allKeys, pipelinesKeys, codePipelineKeys, codebuildKeys
+	artifacts, pager, table, viewport := artifactKeys, pagerKeys, tableKeys, viewportKeys
+	t.Cleanup(func() {
+		allKeys, pipelinesKeys, codePipelineKeys, codebuildKeys = global, pipelines, pipeline, codebuild
+		artifactKeys, pagerKeys, tableKeys, viewportKeys = artifacts, pager, table, viewport
+	})
+}
+
+func TestConfigureKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		keys map[string]map[string][]string
+		// err is a substring of the error, empty when the keys are valid
+		err string
+	}{
+		{"default keys", nil, ""},
+		{"rebound key", map[string]map[string][]string{"pipelines": {"sort": {"S"}}}, ""},
+		{"key of another view", map[string]map[string][]string{"codebuild": {"log": {"a"}}}, ""},
+		{"conflict with a global key", map[string]map[string][]string{"pipelines": {"sort": {"r"}}},
+			`pipelines view: "r" is bound to global.refresh and pipelines.sort`},
+		{"conflict in a view", map[string]map[string][]string{"pipeline": {"approve": {"d"}}},
+			`pipeline view: "d" is bound to pipeline.approve and pipeline.definition`},
+		{"disabled binding", map[string]map[string][]string{"pipeline": {"approve": {"d"}, "definition": {}}}, ""},
+		{"navigation keys follow the global keys", map[string]map[string][]string{"global": {"up": {"up", "w"}}},
+			`pipelines view: "w" is bound to global.up and pipelines.saveView`},
+		{"conflict with a navigation key", map[string]map[string][]string{"viewport": {"pageUp": {"v"}}},
+			`pager view: "v" is bound to viewport.pageUp and pager.format`},
+		{"conflict in the status line", map[string]map[string][]string{"pager": {"confirm": {"esc"}}},
+			`input view: "esc" is bound to pager.cancel and pager.confirm`},
+		{"unknown context", map[string]map[string][]string{"palette": {"up": {"k"}}}, "unknown keys context palette"},
+		{"unknown key", map[string]map[string][]string{"global": {"jump": {"J"}}}, "unknown key global.jump"},
+		{"line keys are global", map[string]map[string][]string{"table": {"lineUp": {"k"}}}, "unknown key table.lineUp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restoreKeys(t)
+			err := ConfigureKeys(tt.keys)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("ConfigureKeys(%v): %v", tt.keys, err)
+			case tt.err != "" && err == nil:
+				t.Errorf("ConfigureKeys(%v) succeeded, want an error with %q", tt.keys, tt.err)
+			case tt.err != "" && !strings.Contains(err.Error(), tt.err):
+				t.Errorf("ConfigureKeys(%v) = %v, want an error with %q", tt.keys, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestConfigureKeysHelp(t *testing.T) {
+	restoreKeys(t)
+	if err := ConfigureKeys(map[string]map[string][]string{"global": {"up": {"up", "w"}, "select": {"enter"}, "refresh": {"ctrl+r", "right"}}, "pipelines": {"saveView": {"ctrl+s"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if help := allKeys.Up.Help(); help.Key != "↑/w" || help.Desc != "move up" {
+		t.Errorf("global.up help = %+v", help)
+	}
+	if help := allKeys.Refresh.Help(); help.Key != "ctrl+r/→" {
+		t.Errorf("global.refresh help = %+v", help)
+	}
+	if keys := tableKeys.LineUp.Keys(); len(keys) != 2 || keys[1] != "w" {
+		t.Errorf("table.lineUp keys = %v, want the global up keys", keys)
+	}
+	if keys := viewportKeys.Up.Keys(); len(keys) != 2 || keys[1] != "w" {
+		t.Errorf("viewport.up keys = %v, want the global up keys", keys)
+	}
+}
@@ -6,6 +6,8 @@ import (
 
 	awsqueries "github.com/fabio42/codeplumber/aws"
 	"github.com/fabio42/codeplumber/cmd/vcr"
+	"github.com/fabio42/codeplumber/events"
+	"github.com/fabio42/codeplumber/notify"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/charmbracelet/bubbles/key"
@@ -24,22 +26,46 @@ const (
 	response   = "inputResponse"
 	previous   = "previous"
 	errorMsg   = "error"
+	infoMsg    = "info"
 	searchMsg  = "search"
+	paletteMsg = "palette"
 	viewUpdate = "viewData"
 	viewChange = "viewChange"
+	eventMsg   = "event"
+	pollMsg    = "poll"
+	sourceMsg  = "source"
+	notifyMsg  = "notify"
 	rowsMsg    = "rows"
 	// View class
-	pipelinesView = "pipelines"
-	pipelineView  = "pipeline"
-	codebuildView = "codebuild"
-	buildspecView = "buildspec"
-	logView       = "log"
+	pipelinesView      = "pipelines"
+	pipelineView       = "pipeline"
+	codebuildView      = "codebuild"
+	buildspecView      = "buildspec"
+	logView            = "log"
+	actionView         = "action"
+	cloudformationView = "cloudformation"
+	ecsView            = "ecs"
+	codedeployView     = "codedeploy"
+	lambdaView         = "lambda"
+	s3View             = "s3"
+	changesetView      = "changeset"
+	sourceView         = "source"
+	artifactsView      = "artifacts"
+	archiveView        = "archive"
+	definitionView     = "definition"
+	diffView           = "diff"
+	dashboardView      = "dashboard"
 )
 
 var (
 	config         Config
-	supportedViews = []string{pipelinesView, pipelineView, codebuildView, buildspecView, logView}
-	supportFilter  = []string{pipelinesView}
+	supportedViews = []string{
+		pipelinesView, pipelineView, codebuildView, buildspecView, logView,
+		actionView, cloudformationView, ecsView, codedeployView, lambdaView, s3View,
+		changesetView, sourceView, artifactsView, archiveView, definitionView, diffView,
+		dashboardView,
+	}
+	supportFilter = []string{pipelinesView}
 )
 
 // Config is the configuration for the TUI
@@ -47,10 +73,22 @@ type Config struct {
 	AwsConfig       aws.Config
 	Recorder        *vcr.Recorder
 	RecordDir       string
-	NameFilter      string
+	NameFilter      awsqueries.NameFilter
 	NameFilterExtra string
-	TagFilter       map[string]string
+	TagFilter       awsqueries.TagFilter
+	Concurrency     int
+	CacheDir        string
+	CacheTTL        time.Duration
+	EventSource     events.Source
+	PollInterval    time.Duration
+	SourceCheckouts map[string]string
+	Columns         []Column
+	Sort            string
+	Dashboard       bool
+	Split           bool
+	StatePath       string
 	Theme           string
+	Notifier        *notify.Notifier
 	Mode            struct {
 		Record, Replay bool
 	}
@@ -77,8 +115,14 @@ type Model struct {
 	pipelinesTable  *PipelinesTable
 	pipelineDetail  *PipelineTable
 	codeBuildDetail *CodeBuildTable
+	actionDetail    *ActionTable
+	changeSet       *ChangeSetTable
+	artifacts       *ArtifactsTable
+	archive         *ArchiveTable
 	pager           *Pager
+	dashboard       *Dashboard
 	spinner         spinner.Model
+	split           splitLayout
 
 	quitting bool
 	ui       *uiData
@@ -132,20 +176,38 @@ func NewModel(cfg Config) *Model {
 		path:  make([]string, len(supportedViews)),
 	}
 
-	return &Model{
+	m := &Model{
 		statusLine:      NewStatusLines(ui),
 		pipelinesTable:  NewPipelinesTable(ui),
 		pipelineDetail:  NewPipelineTable(ui),
 		codeBuildDetail: NewCodeBuildTable(ui),
+		actionDetail:    NewActionTable(ui),
+		changeSet:       NewChangeSetTable(ui),
+		artifacts:       NewArtifactsTable(ui),
+		archive:         NewArchiveTable(ui),
 		pager:           NewPager(ui),
+		dashboard:       NewDashboard(ui),
 		spinner:         s,
 		ui:              ui,
 	}
+	m.split.enabled = config.Split
+	ui.split = config.Split
+	return m
 }
 
 // Init initializes the parent model
 func (m *Model) Init() tea.Cmd {
 	log.Debug().Str("model", "tui").Str("func", "Model.Init").Msg("new model")
+
+	// Views are updated from events when a source is configured, polled otherwise
+	switch {
+	case config.Mode.Replay:
+	case config.EventSource != nil:
+		go m.ui.listenEvents(config.EventSource)
+	case config.PollInterval > 0:
+		go m.ui.poll(config.PollInterval)
+	}
+
 	return tea.Batch(
 		tea.EnterAltScreen,
 		tea.ClearScreen,
@@ -168,7 +230,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	// Initialize the model if not ready
-	if !m.ui.initialized && !m.ui.refreshing {
+	if !m.ui.initialized && !m.ui.refreshing && !m.ui.listing {
 		m.pipelinesTable.refresh()
 	}
 
@@ -183,8 +245,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.pipelinesTable.Update(msg)
 		m.pipelineDetail.Update(msg)
 		m.codeBuildDetail.Update(msg)
+		m.actionDetail.Update(msg)
+		m.changeSet.Update(msg)
+		m.artifacts.Update(msg)
+		m.archive.Update(msg)
 		m.statusLine.Update(msg)
 		m.pager.Update(msg)
+		m.dashboard.Update(msg)
+		if m.isSplit() {
+			m.resizePanes()
+		}
 
 		return m, tea.Batch(cmds...)
 
@@ -193,12 +263,14 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		activeModel := m.getActiveModel()
 		if !m.ui.refreshing {
 			switch {
-			case key.Matches(msg, allKeys.Quit):
+			case msg.Type == tea.KeyCtrlC, key.Matches(msg, allKeys.Quit) && !m.ui.inputFocused:
 				m.quitting = true
 				return m, tea.Quit
+			case m.ui.inputFocused:
+				// The other keys are typed in the status line
 			case key.Matches(msg, allKeys.Help):
 				m.ui.help = !m.ui.help
-				activeModel.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+				m.resize()
 			case key.Matches(msg, allKeys.PrevTint):
 				// TODO: document it in help
 				tint.PreviousTint()
@@ -207,36 +279,76 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				tint.NextTint()
 				activeModel.Update(redraw{})
 			}
-			if m.ui.inputFocused {
+			switch {
+			case m.ui.inputFocused:
 				m.statusLine.Update(msg)
-			} else {
+			case key.Matches(msg, allKeys.Palette):
+				m.statusLineMessage(paletteMsg, m.ui.currentView(), "", m.paletteItems())
+			case key.Matches(msg, allKeys.Split):
+				cmds = append(cmds, m.toggleSplit())
+			case m.isSplit() && m.splitKey(msg):
+				// The focus moved to another pane
+			default:
 				activeModel.Update(msg)
+				if m.isSplit() && m.split.focus != detailPane {
+					// The next panes follow the cursor
+					cmds = append(cmds, m.syncPanes(splitSyncDelay))
+				}
 			}
 		}
 
+	case tea.MouseMsg:
+		if !m.ui.refreshing && !m.ui.inputFocused {
+			cmds = append(cmds, m.mouse(msg))
+		}
+
+	case splitSync:
+		m.updatePanes(msg)
+
 	case tuiMsg:
 		log.Debug().Str("model", "tui").Str("func", "Model.Update").Msgf("tuiMsg: %v", msg)
 		activeModel := m.getActiveModel()
 
 		switch msg.class {
 		case viewUpdate:
-			activeModel.Update(msg)
+			// Updates are routed to their view, a background refresh can complete after the view changed
+			m.getModel(msg.id).Update(msg)
+			if msg.id == pipelinesView && m.isSplit() {
+				if rows, ok := msg.data.(pipelinesRows); ok && rows.pipelines != nil && !rows.stale && m.split.polled {
+					m.split.polled = false
+					m.pipelineDetail.Update(refresh{})
+				}
+				cmds = append(cmds, m.syncPanes(0))
+			}
 
 		case viewChange:
 			if !supportedView(msg.id) {
 				m.statusLineMessage("error", msg.src, fmt.Sprintf("unknown view: %v", msg.class), nil)
 				break
 			}
+			if m.routeSplitView(msg) {
+				break
+			}
 			m.ui.views = append(m.ui.views, msg.id)
 			m.ui.viewIdx++
 			activeModel = m.getActiveModel()
+			if m.split.enabled {
+				// The view may have been sized as a pane
+				activeModel.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+			}
 			activeModel.Update(msg)
 
 		case previous:
+			if m.ui.viewIdx == 0 {
+				break
+			}
 			m.ui.views = m.ui.views[:m.ui.viewIdx]
 			m.ui.viewIdx--
 			activeModel = m.getActiveModel()
-			activeModel.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+			m.resize()
+			if m.isSplit() {
+				m.updateSplitPath()
+			}
 			if msg.trigger {
 				// Sleep 1s to allow the job to start
 				time.Sleep(1 * time.Second)
@@ -245,15 +357,49 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case errorMsg:
 			m.statusLineMessage(errorMsg, msg.src, msg.data.(string), msg.reference)
 
+		case infoMsg:
+			m.statusLineMessage(infoMsg, msg.src, msg.data.(string), msg.reference)
+
 		case input:
 			m.statusLineMessage(msg.id, msg.src, msg.data.(string), msg.reference)
 
 		case response:
+			if msg.id == paletteMsg {
+				m.runPalette(msg.reference.(paletteItem))
+				break
+			}
 			activeModel.Update(msg)
 
+		case eventMsg:
+			if m.ui.isOpen(msg.id) || m.paneOpen(msg.id) {
+				m.getModel(msg.id).Update(msg)
+			}
+
+		case sourceMsg:
+			m.ui.applyEvent(msg.data.(pipelineEvent))
+
+		case notifyMsg:
+			for _, n := range msg.data.([]notify.Notification) {
+				config.Notifier.Notify(n)
+			}
+
+		case pollMsg:
+			switch m.ui.currentView() {
+			case pipelinesView, pipelineView, dashboardView:
+				if m.ui.refreshing || m.ui.revalidating || m.ui.inputFocused {
+					break
+				}
+				if m.isSplit() {
+					// The panes are refreshed one after the other, the stages pane once the listing is merged
+					m.split.polled = m.pipelineDetail.name != ""
+					m.pipelinesTable.Update(refresh{})
+					break
+				}
+				activeModel.Update(refresh{})
+			}
+
 		default:
-			activeModel = m.getActiveModel()
-			activeModel.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+			m.resize()
 		}
 
 		log.Debug().Str("model", "tui").Str("func", "Model.Update").Msgf("views: %v / viewIdx: %v", m.ui.views, m.ui.viewIdx)
@@ -278,13 +424,16 @@ func (m *Model) View() string {
 	}
 
 	var spinner string
-	if m.ui.refreshing {
+	if m.ui.refreshing || m.ui.revalidating {
 		spinner = m.spinner.View()
 	} else {
 		spinner = " "
 	}
 
-	activeModel := m.getActiveModel()
+	content := containerStyle.Render(m.getActiveModel().View())
+	if m.isSplit() {
+		content = m.splitView()
+	}
 	return lipgloss.JoinVertical(
 		lipgloss.Top,
 		lipgloss.JoinHorizontal(
@@ -292,7 +441,7 @@ func (m *Model) View() string {
 			spinner,
 			containerStyle.Render(m.statusLine.View()),
 		),
-		containerStyle.Render(activeModel.View()),
+		content,
 	)
 }
 
@@ -311,17 +460,36 @@ func (m *Model) statusLineMessage(id, src, msg string, ref interface{}) {
 }
 
 func (m *Model) getActiveModel() tea.Model {
-	switch m.ui.views[m.ui.viewIdx] {
+	if m.isSplit() {
+		if model := m.paneModel(m.split.focus); model != nil {
+			return model
+		}
+	}
+	return m.getModel(m.ui.views[m.ui.viewIdx])
+}
+
+func (m *Model) getModel(view string) tea.Model {
+	switch view {
 	case "pipelines":
 		return m.pipelinesTable
 	case "pipeline":
 		return m.pipelineDetail
 	case "codebuild":
 		return m.codeBuildDetail
-	case "buildspec", "log":
+	case actionView, cloudformationView, ecsView, codedeployView, lambdaView, s3View, sourceView:
+		return m.actionDetail
+	case changesetView:
+		return m.changeSet
+	case artifactsView:
+		return m.artifacts
+	case archiveView:
+		return m.archive
+	case "buildspec", "log", definitionView, diffView:
 		return m.pager
+	case dashboardView:
+		return m.dashboard
 	default:
-		log.Fatal().Msgf("unknown active model %v", m.ui.views[m.ui.viewIdx])
+		log.Fatal().Msgf("unknown model %v", view)
 	}
 	return nil
 }
@@ -18,11 +18,20 @@ type uiData struct {
 	viewIdx       int // TODO: Should be moved to Model
 	views         []string
 	refreshing    bool
+	revalidating  bool // cached data is displayed while refreshed in the background
+	listing       bool // the pipelines are listed, set on the Update loop before the listing starts
 	inputFocused  bool
 	initialized   bool
 	path          []string
 	width, height int
 	help          bool
+	query         string   // filter query of the pipelines view
+	queryHistory  []string // filter queries of the session, the latest last
+	savedView     string   // name of the saved view of the pipelines view
+	split         bool     // the pipelines view is displayed in panes with the pipeline and action views
+
+	// states are the states of the pipelines last observed, their transitions notify
+	states pipelineStates
 }
 
 func (c *uiData) startSpinner() {
@@ -35,11 +44,23 @@ func (c *uiData) stopSpinner() {
 	c.statusCmd = ""
 }
 
+// addQuery sets the filter query of the pipelines view and adds it to the history
+func (c *uiData) addQuery(query string) {
+	c.query = query
+	if query != "" && (len(c.queryHistory) == 0 || c.queryHistory[len(c.queryHistory)-1] != query) {
+		c.queryHistory = append(c.queryHistory, query)
+	}
+}
+
 func (c *uiData) currentView() string {
 	return c.views[c.viewIdx]
 }
 
 func (c *uiData) updatPath(name string) {
+	// The path of the panes is set by the layout
+	if c.split && c.viewIdx == 0 {
+		return
+	}
 	if c.path[c.viewIdx] != name {
 		c.path[c.viewIdx] = name
 	}
@@ -53,6 +74,14 @@ func (c *uiData) errorMsg(src, msg string) {
 	}
 }
 
+func (c *uiData) infoMsg(src, msg string) {
+	c.selection <- tuiMsg{
+		class: infoMsg,
+		src:   src,
+		data:  msg,
+	}
+}
+
 func (c *uiData) confirm(src, msg string, ref interface{}) {
 	c.selection <- tuiMsg{
 		class:     input,
@@ -111,6 +140,17 @@ func (c *uiData) changeView(src, dst string, selection interface{}) {
 	}
 }
 
+// changeViewWithTrigger changes the view and runs the main command of the new view once it is loaded
+func (c *uiData) changeViewWithTrigger(src, dst string, selection interface{}) {
+	c.selection <- tuiMsg{
+		class:   viewChange,
+		src:     src,
+		id:      dst,
+		trigger: true,
+		data:    selection,
+	}
+}
+
 func (c *uiData) previousView() {
 	c.selection <- tuiMsg{
 		class: previous,
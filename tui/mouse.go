@@ -0,0 +1,89 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog/log"
+)
+
+// statusPathOffset is the width of the spinner and of the padding before the path of the status line
+const statusPathOffset = 2
+
+// mouse routes the mouse events to the view under the pointer, the coordinates are made relative to the view
+// A click on a segment of the path of the status line navigates back to its view
+func (m *Model) mouse(msg tea.MouseMsg) tea.Cmd {
+	if msg.Action != tea.MouseActionPress {
+		return nil
+	}
+
+	if msg.Y == 0 {
+		if msg.Button != tea.MouseButtonLeft {
+			return nil
+		}
+		if idx, ok := m.statusLine.pathSegment(msg.X - statusPathOffset); ok {
+			m.backTo(idx)
+		}
+		return nil
+	}
+
+	if m.isSplit() {
+		return m.mousePane(msg)
+	}
+	// The views are displayed below the status line, in the padding of the container
+	msg.X, msg.Y = msg.X-1, msg.Y-1
+	_, cmd := m.getActiveModel().Update(msg)
+	return cmd
+}
+
+// mousePane routes the mouse events to the pane under the pointer, which is focused
+func (m *Model) mousePane(msg tea.MouseMsg) tea.Cmd {
+	listWidth, stagesHeight := m.panesSize()
+
+	// The panes are displayed below the status line, within their borders
+	pane, x, y := listPane, msg.X-1, msg.Y-2
+	switch {
+	case msg.X >= listWidth && msg.Y-1 < stagesHeight:
+		pane, x = stagesPane, msg.X-listWidth-1
+	case msg.X >= listWidth:
+		pane, x, y = detailPane, msg.X-listWidth-1, msg.Y-stagesHeight-2
+	}
+	model := m.paneModel(pane)
+	if model == nil {
+		return nil
+	}
+
+	m.split.focus = pane
+	msg.X, msg.Y = x, y
+	_, cmd := model.Update(msg)
+	if pane == detailPane {
+		return cmd
+	}
+	// The next panes follow the cursor
+	return tea.Batch(cmd, m.syncPanes(splitSyncDelay))
+}
+
+// backTo closes the views opened after the view idx of the path, in the split layout the pane idx is focused
+// The pipeline view is refreshed, like when going back to it with the previous key
+func (m *Model) backTo(idx int) {
+	log.Debug().Str("model", "tui").Str("func", "Model.backTo").Msgf("idx: %v, views: %v", idx, m.ui.views)
+
+	if m.isSplit() {
+		if m.paneModel(idx) != nil {
+			m.split.focus = idx
+		}
+		return
+	}
+	if idx >= m.ui.viewIdx {
+		return
+	}
+
+	m.ui.views = m.ui.views[:idx+1]
+	m.ui.viewIdx = idx
+	m.resize()
+	if m.isSplit() {
+		m.updateSplitPath()
+		return
+	}
+	if m.ui.currentView() == pipelineView {
+		m.getActiveModel().Update(refresh{})
+	}
+}
@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"sort"
+	"sync"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+	"github.com/fabio42/codeplumber/events"
+	"github.com/fabio42/codeplumber/notify"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline/types"
+	"github.com/rs/zerolog/log"
+)
+
+// pipelineState is the state of a pipeline when it was observed, the notifications are the transitions between two states
+type pipelineState struct {
+	execution string
+	status    string
+	// approvals are the approval actions waiting for a review by execution, stage and action, nil when unknown
+	approvals map[string]notify.Notification
+}
+
+// pipelineStates are the states of the pipelines last observed, the pipelines are observed from concurrent refreshes
+type pipelineStates struct {
+	mu     sync.Mutex
+	states map[string]pipelineState
+}
+
+// update records the state of a pipeline and returns the transitions since its previous state
+// The first state of a pipeline does not notify, it was reached before codeplumber was started
+func (s *pipelineStates) update(name string, state pipelineState) []notify.Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.states == nil {
+		s.states = make(map[string]pipelineState)
+	}
+
+	previous, known := s.states[name]
+	if state.approvals == nil && previous.execution == state.execution && state.status == string(types.PipelineExecutionStatusInProgress) {
+		// The approvals are not known from the listing, those of the execution were already notified
+		state.approvals = previous.approvals
+	}
+	s.states[name] = state
+	if !known {
+		return nil
+	}
+
+	var notifications []notify.Notification
+	if state.execution != previous.execution || state.status != previous.status {
+		switch types.PipelineExecutionStatus(state.status) {
+		case types.PipelineExecutionStatusInProgress:
+			notifications = append(notifications, notify.Notification{Pipeline: name, Transition: notify.Started})
+		case types.PipelineExecutionStatusSucceeded:
+			notifications = append(notifications, notify.Notification{Pipeline: name, Transition: notify.Succeeded})
+		case types.PipelineExecutionStatusFailed:
+			notifications = append(notifications, notify.Notification{Pipeline: name, Transition: notify.Failed})
+		}
+	}
+	// The approvals of a new execution are all new
+	if previous.approvals != nil || state.execution != previous.execution {
+		keys := make([]string, 0, len(state.approvals))
+		for key := range state.approvals {
+			if _, ok := previous.approvals[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			notifications = append(notifications, state.approvals[key])
+		}
+	}
+	return notifications
+}
+
+// changed returns true when the pipeline was observed with another execution
+func (s *pipelineStates) changed(name, execution string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, known := s.states[name]
+	return known && state.execution != execution
+}
+
+// approve records an approval action waiting for a review, it returns false when it was already notified
+func (s *pipelineStates) approve(name, execution string, approval notify.Notification) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.states == nil {
+		s.states = make(map[string]pipelineState)
+	}
+
+	state, known := s.states[name]
+	if !known || state.execution != execution {
+		state = pipelineState{execution: execution, status: string(types.PipelineExecutionStatusInProgress)}
+	}
+	if state.approvals == nil {
+		state.approvals = make(map[string]notify.Notification)
+	}
+	key := approvalKey(execution, approval.Stage, approval.Action)
+	if _, ok := state.approvals[key]; ok {
+		return false
+	}
+	state.approvals[key] = approval
+	s.states[name] = state
+	return true
+}
+
+func approvalKey(execution, stage, action string) string {
+	return execution + "/" + stage + "/" + action
+}
+
+// waitingApprovals returns the approval actions of a pipeline waiting for a review, nil when the state of its stages is not known
+// A pipeline which is not in progress has no approval waiting
+func waitingApprovals(p awsqueries.Pipeline) map[string]notify.Notification {
+	approvals := make(map[string]notify.Notification)
+	switch {
+	case p.LastExecutionStatus != string(types.PipelineExecutionStatusInProgress):
+		return approvals
+	case p.StateData == nil:
+		return nil
+	}
+
+	for _, stage := range p.StateData.StageStates {
+		if stage.LatestExecution == nil {
+			continue
+		}
+		for _, action := range stage.ActionStates {
+			// Only the approval actions waiting for a review have a token
+			if action.LatestExecution == nil || action.LatestExecution.Status != types.ActionExecutionStatusInProgress || action.LatestExecution.Token == nil {
+				continue
+			}
+			key := approvalKey(aws.ToString(stage.LatestExecution.PipelineExecutionId), aws.ToString(stage.StageName), aws.ToString(action.ActionName))
+			approvals[key] = notify.Notification{
+				Pipeline:   p.PipelineName,
+				Transition: notify.Approval,
+				Stage:      aws.ToString(stage.StageName),
+				Action:     aws.ToString(action.ActionName),
+			}
+		}
+	}
+	return approvals
+}
+
+// observe sends the transitions of the pipelines since they were last observed to the Update loop
+// The state of the stages of a pipeline in progress is queried when its execution changed and its approvals notify
+// It is called from the refreshes running in the background
+func (c *uiData) observe(pipelines ...awsqueries.Pipeline) {
+	if config.Notifier == nil {
+		return
+	}
+
+	var notifications []notify.Notification
+	for _, p := range pipelines {
+		if p.Error != "" || p.LastExecutionID == "" {
+			continue
+		}
+		if p.StateData == nil && p.LastExecutionStatus == string(types.PipelineExecutionStatusInProgress) &&
+			!config.Mode.Replay && config.Notifier.Watches(p.PipelineName, notify.Approval) && c.states.changed(p.PipelineName, p.LastExecutionID) {
+			state, err := awsqueries.GetPipelineState(config.AwsConfig, p.PipelineName)
+			if err != nil {
+				log.Debug().Str("model", "tui").Str("func", "observe").Msgf("failed to get the state of %v: %v", p.PipelineName, err)
+			}
+			p.StateData = state
+		}
+
+		state := pipelineState{execution: p.LastExecutionID, status: p.LastExecutionStatus, approvals: waitingApprovals(p)}
+		notifications = append(notifications, c.states.update(p.PipelineName, state)...)
+	}
+	c.notify(notifications)
+}
+
+// observeApproval sends the approval action of an event to the Update loop, unless it was already notified
+func (c *uiData) observeApproval(e events.Event) {
+	if config.Notifier == nil {
+		return
+	}
+
+	approval := notify.Notification{Pipeline: e.Pipeline, Transition: notify.Approval, Stage: e.Stage, Action: e.Action}
+	if c.states.approve(e.Pipeline, e.ExecutionID, approval) {
+		c.notify([]notify.Notification{approval})
+	}
+}
+
+// notify sends notifications to the Update loop, where the notifier writes its escape sequences
+func (c *uiData) notify(notifications []notify.Notification) {
+	if len(notifications) == 0 {
+		return
+	}
+	c.selection <- tuiMsg{
+		class: notifyMsg,
+		data:  notifications,
+	}
+}
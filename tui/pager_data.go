@@ -2,12 +2,16 @@ package tui
 
 import (
 	"fmt"
+	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 
 	awsqueries "github.com/fabio42/codeplumber/aws"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	codebuildtypes "github.com/aws/aws-sdk-go-v2/service/codebuild/types"
 	"github.com/rs/zerolog/log"
 )
 
@@ -52,3 +56,138 @@ func refreshLogOps(p *Pager) {
 		content: logStream.String(),
 	})
 }
+
+func (m *Pager) refreshBuildspec() {
+	go refreshBuildspecOps(m)
+}
+
+func refreshBuildspecOps(p *Pager) {
+	var content string
+	var err error
+
+	p.ui.startSpinner()
+
+	record := fmt.Sprintf("Buildspec-%s.txt", p.name)
+	if config.Mode.Replay {
+		content, err = config.Recorder.GetBuildspec(record)
+	} else {
+		content, err = getBuildspec(p.ui.dataCache.codebuilds[p.name])
+		config.Recorder.Record(record, content)
+	}
+
+	p.ui.stopSpinner()
+	if err != nil {
+		p.ui.errorMsg(buildspecView, fmt.Sprintf("failed to get buildspec: %v", err))
+		return
+	}
+
+	p.ui.updateView(buildspecView, PagerSelector{
+		name:    p.name,
+		content: renderYaml(p.Width, content),
+	})
+}
+
+// getBuildspec resolve a non-inline buildspec from the source version used by the build
+func getBuildspec(cb awsqueries.CodebuildData) (string, error) {
+	build := cb.Builds.Builds[0]
+	buildspec := buildspecPath(cb)
+
+	// Buildspec stored in S3, this is not related to the build source
+	if strings.HasPrefix(buildspec, "arn:") {
+		bucket, key, err := awsqueries.ParseS3Location(buildspec)
+		if err != nil {
+			return "", err
+		}
+		b, err := awsqueries.GetS3Object(config.AwsConfig, bucket, key, "")
+		return string(b), err
+	}
+
+	sourceType := codebuildtypes.SourceTypeNoSource
+	if build.Source != nil {
+		sourceType = build.Source.Type
+	}
+
+	switch sourceType {
+	case codebuildtypes.SourceTypeCodepipeline, codebuildtypes.SourceTypeS3:
+		// CodePipeline provides the source artifact location as the build source version
+		location := aws.ToString(build.SourceVersion)
+		version := ""
+		if sourceType == codebuildtypes.SourceTypeS3 {
+			location = aws.ToString(build.Source.Location)
+			version = aws.ToString(build.SourceVersion)
+		}
+		bucket, key, err := awsqueries.ParseS3Location(location)
+		if err != nil {
+			return "", err
+		}
+		b, err := awsqueries.GetS3ArchiveFile(config.AwsConfig, bucket, key, version, buildspec)
+		return string(b), err
+
+	case codebuildtypes.SourceTypeCodecommit:
+		repository := path.Base(aws.ToString(build.Source.Location))
+		b, err := awsqueries.GetCodeCommitFile(config.AwsConfig, repository, aws.ToString(build.ResolvedSourceVersion), buildspec)
+		return string(b), err
+
+	case codebuildtypes.SourceTypeNoSource:
+		return "", fmt.Errorf("build has no source")
+
+	default:
+		location := aws.ToString(build.Source.Location)
+		dir, ok := findSourceCheckout(location)
+		if !ok {
+			return "", fmt.Errorf("no local checkout configured for %s", location)
+		}
+		return readLocalCheckout(dir, aws.ToString(build.ResolvedSourceVersion), buildspec)
+	}
+}
+
+// buildspecPath returns the buildspec used by the build, CodeBuild default to buildspec.yml when not set
+func buildspecPath(cb awsqueries.CodebuildData) string {
+	build := cb.Builds.Builds[0]
+	if build.Source != nil && aws.ToString(build.Source.Buildspec) != "" {
+		return aws.ToString(build.Source.Buildspec)
+	}
+	project := cb.Project.Projects[0]
+	if project.Source != nil && aws.ToString(project.Source.Buildspec) != "" {
+		return aws.ToString(project.Source.Buildspec)
+	}
+	return "buildspec.yml"
+}
+
+func isInlineBuildspec(buildspec string) bool {
+	return strings.HasPrefix(buildspec, "version:") || strings.Contains(buildspec, "\n")
+}
+
+// findSourceCheckout returns the local checkout configured for a source repository location
+func findSourceCheckout(location string) (string, bool) {
+	repository := normalizeRepository(location)
+	for k, dir := range config.SourceCheckouts {
+		if normalizeRepository(k) == repository {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+// normalizeRepository strips the scheme and suffixes from a repository location
+// so https://github.com/org/repo.git, git@github.com:org/repo and github.com/org/repo are equal
+func normalizeRepository(location string) string {
+	location = strings.ToLower(strings.TrimSpace(location))
+	for _, prefix := range []string{"https://", "http://", "ssh://", "git@"} {
+		location = strings.TrimPrefix(location, prefix)
+	}
+	location = strings.Replace(location, ":", "/", 1)
+	location = strings.TrimSuffix(location, "/")
+	return strings.TrimSuffix(location, ".git")
+}
+
+func readLocalCheckout(dir, commit, filePath string) (string, error) {
+	if commit == "" {
+		commit = "HEAD"
+	}
+	out, err := exec.Command("git", "-C", dir, "show", fmt.Sprintf("%s:%s", commit, strings.TrimPrefix(filePath, "./"))).Output()
+	if err != nil {
+		return "", fmt.Errorf("git show %s:%s in %s: %w", commit, filePath, dir, err)
+	}
+	return string(out), nil
+}
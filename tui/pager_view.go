@@ -43,6 +43,7 @@ type Pager struct {
 	title        string
 	content      string
 	lastLogToken *string
+	format       int
 	msg          *tuiMsg
 	ui           *uiData
 	help         help.Model
@@ -51,6 +52,9 @@ type Pager struct {
 // NewPager returns a new Pager
 func NewPager(ui *uiData) *Pager {
 	p := viewport.New(1, 1)
+	p.KeyMap = viewportKeys
+	// The content scrolls with the mouse wheel
+	p.MouseWheelEnabled = true
 
 	return &Pager{
 		Model: &p,
@@ -87,7 +91,19 @@ func (m *Pager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, allKeys.Previous):
 			m.ui.previousView()
 		case key.Matches(msg, allKeys.Refresh):
-			m.refreshLog()
+			switch {
+			case m.msg == nil:
+			case m.msg.id == logView:
+				m.refreshLog()
+			case m.msg.id == buildspecView:
+				// Non-inline buildspecs are fetched again from the build source
+				m.SetContent()
+			}
+		case key.Matches(msg, pagerKeys.Format):
+			if m.msg != nil && m.msg.id == definitionView {
+				m.format = (m.format + 1) % len(definitionFormats)
+				m.setDefinition()
+			}
 		}
 
 	case tuiMsg:
@@ -128,27 +144,67 @@ func (m *Pager) SetContent() {
 		m.title = "CodeBuild Buildspec Definition"
 		m.pathTitle = "buildspec"
 
-		renderer, err := glamour.NewTermRenderer(
-			glamour.WithAutoStyle(),
-			glamour.WithWordWrap(m.Width),
-		)
-		if err != nil {
-			log.Fatal().Err(err).Msg("Failed to create renderer")
-		}
-
-		bs := m.ui.dataCache.codebuilds[m.name].Project.Projects[0].Source.Buildspec
-		m.content, err = renderer.Render(fmt.Sprintf("```yaml\n%v\n```", *bs))
-		if err != nil {
-			log.Fatal().Err(err).Msg("Failed to render markdown")
+		bs := buildspecPath(m.ui.dataCache.codebuilds[m.name])
+		if isInlineBuildspec(bs) {
+			m.content = renderYaml(m.Width, bs)
+			m.Model.SetContent(m.content)
+		} else {
+			// Non-inline buildspec are fetched from the build source
+			m.title = "CodeBuild Buildspec Definition " + bs
+			m.refreshBuildspec()
 		}
-		m.Model.SetContent(m.content)
 
 	case logView:
 		m.title = "CodeBuild Exection Log " + m.name
 		m.pathTitle = "cloudwatch-logs"
 		m.reset()
 		m.refreshLog()
+
+	case diffView:
+		m.title = "CodePipeline Diff " + m.name
+		m.pathTitle = "diff"
+		m.content = m.msg.data.(PagerSelector).content
+		m.Model.SetContent(m.content)
+
+	case definitionView:
+		m.pathTitle = "definition"
+		m.format = definitionDiagram
+		m.setDefinition()
+	}
+}
+
+// setDefinition renders the pipeline definition in the selected format
+func (m *Pager) setDefinition() {
+	m.title = fmt.Sprintf("CodePipeline Definition %v (%v)", m.name, definitionFormats[m.format])
+	content, err := renderDefinition(m.Width, m.format, m.name, m.ui)
+	if err != nil {
+		go m.ui.errorMsg(definitionView, err.Error())
+	}
+	m.content = content
+	m.Model.SetContent(m.content)
+	m.GotoTop()
+}
+
+// renderYaml renders a yaml document with syntax highlighting
+func renderYaml(width int, content string) string {
+	return renderCode(width, "yaml", content)
+}
+
+// renderCode renders a document with the syntax highlighting of language
+func renderCode(width int, language, content string) string {
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithAutoStyle(),
+		glamour.WithWordWrap(width),
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create renderer")
+	}
+
+	out, err := renderer.Render(fmt.Sprintf("```%v\n%v\n```", language, content))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to render markdown")
 	}
+	return out
 }
 
 func (m *Pager) headerView() string {
@@ -175,7 +231,7 @@ func (m *Pager) helpView() string {
 		allKeys.Up,
 		allKeys.Down,
 		allKeys.Previous,
-		allKeys.Refresh,
+		m.refreshKey(),
 		allKeys.Quit,
 		allKeys.Help,
 	})
@@ -189,9 +245,23 @@ func (m *Pager) helpViewFull() string {
 			allKeys.Previous,
 		},
 		{
-			allKeys.Refresh,
+			viewportKeys.PageDown,
+			viewportKeys.PageUp,
+			viewportKeys.HalfPageDown,
+			viewportKeys.HalfPageUp,
+		},
+		{
+			m.refreshKey(),
 			allKeys.Quit,
 			allKeys.Help,
 		},
 	})
 }
+
+// refreshKey returns the key refreshing the content of the pager, the definition is rendered again in another format
+func (m *Pager) refreshKey() key.Binding {
+	if m.msg != nil && m.msg.id == definitionView {
+		return pagerKeys.Format
+	}
+	return allKeys.Refresh
+}
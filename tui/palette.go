@@ -0,0 +1,314 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline/types"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	tint "github.com/lrstanley/bubbletint"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// Commands of the command palette
+	paletteOpen       = "open"
+	paletteSavedView  = "saved view"
+	paletteStart      = "start"
+	paletteStop       = "stop"
+	paletteDefinition = "definition"
+	paletteApprove    = "approve"
+	paletteReject     = "reject"
+	paletteLogs       = "logs"
+
+	// paletteSeparator separates the pipeline, the stage, the action and the command of the labels
+	paletteSeparator = " > "
+	// paletteMatches is the number of matches displayed in the status line
+	paletteMatches = 5
+	// paletteInputWidth is the width of the palette input
+	paletteInputWidth = 32
+)
+
+// paletteItem is an entry of the command palette: a view, a pipeline, or an action of a pipeline, and a command
+type paletteItem struct {
+	label string
+	// view is the view opened from the pipelines view, pipelineView for the pipelines and their actions
+	view     string
+	pipeline string
+	stage    string
+	action   string
+	command  string
+	// savedView is the index of the saved view of the paletteSavedView command
+	savedView int
+}
+
+// palette is the command palette input of the status line and the items matching the input
+type palette struct {
+	input    textinput.Model
+	items    []paletteItem
+	matches  []paletteItem
+	selected int
+}
+
+func newPalette(s lipgloss.Style) palette {
+	placeholder := "Go to a pipeline, an action or a view"
+	input := newInput(": ", placeholder, s)
+	input.Width = paletteInputWidth
+	input.Placeholder = fmt.Sprintf("%-*v", paletteInputWidth, placeholder)
+	return palette{input: input}
+}
+
+// open resets the palette with the items of the session
+func (p *palette) open(items []paletteItem) {
+	p.items = items
+	p.input.Reset()
+	p.input.Focus()
+	p.match()
+}
+
+// close blurs the palette input
+func (p *palette) close() {
+	p.input.Blur()
+	p.items, p.matches = nil, nil
+}
+
+// update moves the selection or edits the input, it returns true when an item is selected
+func (p *palette) update(msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, pagerKeys.Select):
+		return len(p.matches) > 0
+	case key.Matches(msg, pagerKeys.Up):
+		if p.selected > 0 {
+			p.selected--
+		}
+	case key.Matches(msg, pagerKeys.Down):
+		if p.selected < len(p.matches)-1 {
+			p.selected++
+		}
+	default:
+		value := p.input.Value()
+		p.input, _ = p.input.Update(msg)
+		if p.input.Value() != value {
+			p.match()
+		}
+	}
+	return false
+}
+
+// match selects the best match of the input
+func (p *palette) match() {
+	p.matches = fuzzyFilter(p.input.Value(), p.items)
+	p.selected = 0
+}
+
+func (p *palette) selection() paletteItem {
+	return p.matches[p.selected]
+}
+
+// View renders the input and a page of matches, the selected match is highlighted
+func (p *palette) View(width int) string {
+	var b strings.Builder
+	b.WriteString(p.input.View())
+	b.WriteString("  ")
+
+	if len(p.matches) == 0 {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render("no match"))
+		return lipgloss.NewStyle().MaxWidth(width).Render(b.String())
+	}
+
+	start := p.selected / paletteMatches * paletteMatches
+	end := min(len(p.matches), start+paletteMatches)
+	matches := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		if i == p.selected {
+			matches = append(matches, lipgloss.NewStyle().Bold(true).Foreground(tint.Purple()).Render("▸ "+p.matches[i].label))
+		} else {
+			matches = append(matches, lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render(p.matches[i].label))
+		}
+	}
+	b.WriteString(strings.Join(matches, "  "))
+	if len(p.matches) > paletteMatches {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(fmt.Sprintf("  (%d/%d)", p.selected+1, len(p.matches))))
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(b.String())
+}
+
+// fuzzyScore scores a match of the query characters in order in the label, it returns false when they do not match
+// Matches at the start of a word and consecutive matches score more, gaps score less; spaces of the query are ignored
+func fuzzyScore(query, label string) (int, bool) {
+	q := []rune(strings.ToLower(strings.ReplaceAll(query, " ", "")))
+	l := []rune(strings.ToLower(label))
+
+	score, qi, last := 0, 0, -1
+	for i := 0; i < len(l) && qi < len(q); i++ {
+		if l[i] != q[qi] {
+			continue
+		}
+		score++
+		if i == 0 || strings.ContainsRune(" -_./:>", l[i-1]) {
+			score += 5
+		}
+		switch {
+		case last >= 0 && last == i-1:
+			score += 4
+		case last >= 0:
+			score -= min(i-last-1, 3)
+		}
+		last = i
+		qi++
+	}
+	return score, qi == len(q)
+}
+
+// fuzzyFilter returns the items matching the query, the best matches first, then the shortest labels
+// Items are kept in their order when the query is empty
+func fuzzyFilter(query string, items []paletteItem) []paletteItem {
+	if strings.TrimSpace(query) == "" {
+		return items
+	}
+
+	type match struct {
+		item  paletteItem
+		score int
+	}
+	matches := []match{}
+	for _, item := range items {
+		if score, ok := fuzzyScore(query, item.label); ok {
+			matches = append(matches, match{item, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return len(matches[i].item.label) < len(matches[j].item.label)
+	})
+
+	filtered := make([]paletteItem, len(matches))
+	for i, m := range matches {
+		filtered[i] = m.item
+	}
+	return filtered
+}
+
+// paletteAction is an action of a pipeline, the category and the provider are empty when the definition is not loaded
+type paletteAction struct {
+	stage, action      string
+	category, provider string
+}
+
+// pipelineActions returns the actions of a pipeline, from its definition or from the state of its stages
+// Pipelines which were neither opened nor displayed in the dashboard have no known action
+func pipelineActions(p awsqueries.Pipeline) []paletteAction {
+	actions := []paletteAction{}
+	switch {
+	case p.Data != nil && p.Data.Pipeline != nil:
+		for _, stage := range p.Data.Pipeline.Stages {
+			for _, action := range stage.Actions {
+				a := paletteAction{stage: aws.ToString(stage.Name), action: aws.ToString(action.Name)}
+				if action.ActionTypeId != nil {
+					a.category = string(action.ActionTypeId.Category)
+					a.provider = aws.ToString(action.ActionTypeId.Provider)
+				}
+				actions = append(actions, a)
+			}
+		}
+	case p.StateData != nil:
+		for _, stage := range p.StateData.StageStates {
+			for _, action := range stage.ActionStates {
+				actions = append(actions, paletteAction{stage: aws.ToString(stage.StageName), action: aws.ToString(action.ActionName)})
+			}
+		}
+	}
+	return actions
+}
+
+// pipelineItems returns the commands of a pipeline and of its actions
+func pipelineItems(p awsqueries.Pipeline) []paletteItem {
+	name := p.PipelineName
+	item := func(label, stage, action, command string) paletteItem {
+		return paletteItem{label: label, view: pipelineView, pipeline: name, stage: stage, action: action, command: command}
+	}
+
+	items := []paletteItem{
+		item(name, "", "", paletteOpen),
+		item(name+paletteSeparator+paletteStart, "", "", paletteStart),
+		item(name+paletteSeparator+paletteDefinition, "", "", paletteDefinition),
+	}
+	if p.LastExecutionStatus == string(types.PipelineExecutionStatusInProgress) {
+		items = append(items, item(name+paletteSeparator+paletteStop, "", "", paletteStop))
+	}
+	for _, a := range pipelineActions(p) {
+		label := strings.Join([]string{name, a.stage, a.action}, paletteSeparator)
+		items = append(items, item(label, a.stage, a.action, paletteOpen))
+		switch {
+		case a.category == string(types.ActionCategoryApproval):
+			items = append(items,
+				item(label+paletteSeparator+paletteApprove, a.stage, a.action, paletteApprove),
+				item(label+paletteSeparator+paletteReject, a.stage, a.action, paletteReject),
+			)
+		case a.provider == "CodeBuild":
+			items = append(items, item(label+paletteSeparator+paletteLogs, a.stage, a.action, paletteLogs))
+		}
+	}
+	return items
+}
+
+// paletteItems returns the views, the saved views, and the pipelines listed in the session with their actions
+func (m *Model) paletteItems() []paletteItem {
+	items := []paletteItem{
+		{label: "pipelines", view: pipelinesView, command: paletteOpen},
+		{label: "dashboard", view: dashboardView, command: paletteOpen},
+	}
+	for i, view := range m.pipelinesTable.state.Views {
+		items = append(items, paletteItem{label: "view: " + view.Name, view: pipelinesView, command: paletteSavedView, savedView: i})
+	}
+
+	names := make([]string, 0, len(m.ui.dataCache.pipelines))
+	for name := range m.ui.dataCache.pipelines {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		p := m.ui.dataCache.pipelines[name]
+		p.PipelineName = name
+		items = append(items, pipelineItems(p)...)
+	}
+	return items
+}
+
+// runPalette jumps to the item selected in the command palette, the views are opened from the pipelines view
+func (m *Model) runPalette(item paletteItem) {
+	log.Debug().Str("model", "tui").Str("func", "Model.runPalette").Msgf("item: %v", item.label)
+
+	m.ui.views = m.ui.views[:1]
+	m.ui.viewIdx = 0
+	m.split.focus = listPane
+	m.resize()
+
+	switch item.view {
+	case pipelinesView:
+		if item.command == paletteSavedView {
+			m.pipelinesTable.showView(item.savedView)
+		}
+
+	case dashboardView:
+		go m.ui.changeView(pipelinesView, dashboardView, m.pipelinesTable.rowNames())
+
+	case pipelineView:
+		m.pipelinesTable.selectPipeline(item.pipeline)
+		m.pipelineDetail.target = nil
+		if item.command != paletteOpen || item.action != "" {
+			m.pipelineDetail.target = &item
+		}
+		go m.ui.changeView(pipelinesView, pipelineView, item.pipeline)
+	}
+}
@@ -0,0 +1,199 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	awsqueries "github.com/fabio42/codeplumber/aws"
+	"github.com/fabio42/codeplumber/models/table"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline/types"
+)
+
+const (
+	maxSourceExecutions = 50
+	maxSourceCommits    = 50
+	shortRevisionSize   = 8
+)
+
+// sourceRows returns the revision used by a source action, the revision of the previous successful
+// execution and the changes in between
+func sourceRows(cfg aws.Config, resource PipelineResource) ([]table.Row, error) {
+	if resource.PipelineExecutionID == "" {
+		return []table.Row{{"", ""}, {"Source Revision:", "Not started"}}, nil
+	}
+
+	executions, err := awsqueries.ListPipelineExecutions(config.AwsConfig, resource.PipelineName, maxSourceExecutions)
+	if err != nil {
+		return nil, err
+	}
+
+	var current, previous *types.SourceRevision
+	var previousExecutionID string
+	for i, execution := range executions {
+		if current == nil && aws.ToString(execution.PipelineExecutionId) == resource.PipelineExecutionID {
+			current = findSourceRevision(execution.SourceRevisions, resource.ActionName)
+			continue
+		}
+		if current != nil && execution.Status == types.PipelineExecutionStatusSucceeded {
+			previous = findSourceRevision(executions[i].SourceRevisions, resource.ActionName)
+			previousExecutionID = aws.ToString(execution.PipelineExecutionId)
+			break
+		}
+	}
+	if current == nil {
+		return []table.Row{{"", ""}, {"Source Revision:", "Not found"}}, nil
+	}
+
+	rows := []table.Row{{"", ""}, {"Source Revision:", ""}}
+	rows = append(rows, revisionRows(cfg, resource, current)...)
+
+	rows = append(rows, table.Row{"", ""})
+	if previous == nil {
+		rows = append(rows, table.Row{"Previous Successful Execution:", "Not found"})
+		return rows, nil
+	}
+	rows = append(rows, table.Row{"Previous Successful Execution:", previousExecutionID})
+	rows = append(rows, revisionRows(cfg, resource, previous)...)
+
+	rows = append(rows, table.Row{"", ""})
+	currentID := aws.ToString(current.RevisionId)
+	previousID := aws.ToString(previous.RevisionId)
+	if currentID == previousID {
+		rows = append(rows, table.Row{"Changes:", "Same revision as the previous successful execution"})
+		return rows, nil
+	}
+	rows = append(rows, table.Row{"Changes:", ""})
+	if compareURL := sourceCompareURL(cfg.Region, resource, current, previousID, currentID); compareURL != "" {
+		rows = append(rows, table.Row{"  Compare URL", compareURL})
+	}
+
+	if resource.Provider == "CodeCommit" {
+		commits, err := awsqueries.ListCodeCommitCommits(cfg, resource.Configuration["RepositoryName"], previousID, currentID, maxSourceCommits)
+		if err != nil {
+			return rows, err
+		}
+		for _, commit := range commits {
+			author := ""
+			if commit.Author != nil {
+				author = aws.ToString(commit.Author.Name)
+			}
+			rows = append(rows, table.Row{
+				"  " + shortRevision(aws.ToString(commit.CommitId)) + " " + author,
+				firstLine(aws.ToString(commit.Message)),
+			})
+		}
+	}
+	return rows, nil
+}
+
+func revisionRows(cfg aws.Config, resource PipelineResource, revision *types.SourceRevision) []table.Row {
+	rows := []table.Row{
+		{"  Revision ID", aws.ToString(revision.RevisionId)},
+		{"  Summary", revisionSummary(*revision)},
+	}
+
+	// Only CodeCommit provides the commit author
+	if resource.Provider == "CodeCommit" {
+		author := "N/A"
+		commit, err := awsqueries.GetCodeCommitCommit(cfg, resource.Configuration["RepositoryName"], aws.ToString(revision.RevisionId))
+		if err == nil && commit.Author != nil {
+			author = fmt.Sprintf("%v <%v>", aws.ToString(commit.Author.Name), aws.ToString(commit.Author.Email))
+		}
+		rows = append(rows, table.Row{"  Author", author})
+	}
+	if revision.RevisionUrl != nil {
+		rows = append(rows, table.Row{"  Revision URL", aws.ToString(revision.RevisionUrl)})
+	}
+	return rows
+}
+
+// sourceCompareURL returns an URL comparing two revisions of a source repository
+func sourceCompareURL(region string, resource PipelineResource, revision *types.SourceRevision, from, to string) string {
+	switch resource.Provider {
+	case "CodeCommit":
+		return awsqueries.ConsoleURL(region, fmt.Sprintf(
+			"/codesuite/codecommit/repositories/%v/compare/%v/.../%v?region=%v",
+			resource.Configuration["RepositoryName"], from, to, region))
+	case "GitHub":
+		return fmt.Sprintf("https://github.com/%v/%v/compare/%v...%v", resource.Configuration["Owner"], resource.Configuration["Repo"], from, to)
+	case "CodeStarSourceConnection":
+		repository := resource.Configuration["FullRepositoryId"]
+		switch revisionProvider(*revision) {
+		case "GitHub":
+			return fmt.Sprintf("https://github.com/%v/compare/%v...%v", repository, from, to)
+		case "GitLab":
+			return fmt.Sprintf("https://gitlab.com/%v/-/compare/%v...%v", repository, from, to)
+		case "Bitbucket":
+			return fmt.Sprintf("https://bitbucket.org/%v/branches/compare/%v%%0D%v", repository, to, from)
+		}
+	}
+	return ""
+}
+
+func findSourceRevision(revisions []types.SourceRevision, actionName string) *types.SourceRevision {
+	for i, revision := range revisions {
+		if aws.ToString(revision.ActionName) == actionName {
+			return &revisions[i]
+		}
+	}
+	return nil
+}
+
+// revisionDetails holds the JSON revision summary provided by CodeStar connections
+type revisionDetails struct {
+	ProviderType  string
+	CommitMessage string
+}
+
+func parseRevisionSummary(revision types.SourceRevision) (revisionDetails, bool) {
+	var details revisionDetails
+	summary := aws.ToString(revision.RevisionSummary)
+	if !strings.HasPrefix(summary, "{") {
+		return details, false
+	}
+	return details, json.Unmarshal([]byte(summary), &details) == nil
+}
+
+// revisionSummary returns the commit message of a revision
+func revisionSummary(revision types.SourceRevision) string {
+	if details, ok := parseRevisionSummary(revision); ok {
+		return details.CommitMessage
+	}
+	return aws.ToString(revision.RevisionSummary)
+}
+
+func revisionProvider(revision types.SourceRevision) string {
+	if details, ok := parseRevisionSummary(revision); ok {
+		return details.ProviderType
+	}
+	// The connection redirect URL contains the provider for older executions
+	if u, err := url.Parse(aws.ToString(revision.RevisionUrl)); err == nil {
+		return u.Query().Get("ProviderType")
+	}
+	return ""
+}
+
+// revisionsChanges returns a short description of the revisions used by an execution
+func revisionsChanges(revisions []types.SourceRevision) string {
+	changes := make([]string, len(revisions))
+	for i, revision := range revisions {
+		changes[i] = strings.TrimSpace(shortRevision(aws.ToString(revision.RevisionId)) + " " + firstLine(revisionSummary(revision)))
+	}
+	return strings.Join(changes, "; ")
+}
+
+func shortRevision(revision string) string {
+	if len(revision) > shortRevisionSize {
+		return revision[:shortRevisionSize]
+	}
+	return revision
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
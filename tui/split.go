@@ -0,0 +1,307 @@
+package tui
+
+import (
+	"slices"
+	"time"
+
+	"github.com/fabio42/codeplumber/models/table"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	tint "github.com/lrstanley/bubbletint"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// Panes of the split layout
+	listPane = iota
+	stagesPane
+	detailPane
+
+	// splitSyncDelay is the time the cursor has to rest on a row before the next panes are updated
+	splitSyncDelay = 300 * time.Millisecond
+	// splitStatusWidth is the width of the status column in the list pane
+	splitStatusWidth = 12
+)
+
+// splitLayout displays the pipelines, the stages of the selected pipeline and the selected action side by side
+// The panes are displayed while the pipelines view is the current view, the other views are displayed full screen
+type splitLayout struct {
+	enabled bool
+	focus   int
+	// detail is the view of the action displayed in the detail pane, empty when none is selected
+	detail string
+	// resource identifies the action displayed in the detail pane
+	resource string
+	// seq identifies the latest cursor move, only the latest move updates the panes
+	seq int
+
+	// polled is set while a polled listing refresh runs, the stages pane is refreshed once the listing is merged
+	polled bool
+}
+
+// splitSync updates the panes once the cursor rests on a row
+type splitSync struct {
+	seq int
+}
+
+// detailViews are the views of the actions displayed in the detail pane
+var detailViews = []string{codebuildView, actionView, cloudformationView, ecsView, codedeployView, lambdaView, s3View, sourceView}
+
+// isSplit returns true when the panes are displayed
+func (m *Model) isSplit() bool {
+	return m.split.enabled && m.ui.viewIdx == 0
+}
+
+// toggleSplit switches between the split layout and the full screen views
+// The focused pane becomes the current view when the split layout is disabled, and the reverse
+func (m *Model) toggleSplit() tea.Cmd {
+	m.split.enabled = !m.split.enabled
+	m.ui.split = m.split.enabled
+
+	if m.split.enabled {
+		m.split.focus = listPane
+		m.ui.views = m.ui.views[:1]
+		m.ui.viewIdx = 0
+		m.resizePanes()
+		m.updateSplitPath()
+		return m.syncPanes(0)
+	}
+
+	views := []string{pipelinesView}
+	if m.split.focus >= stagesPane && m.pipelineDetail.name != "" {
+		views = append(views, pipelineView)
+		if m.split.focus == detailPane && m.split.detail != "" {
+			views = append(views, m.split.detail)
+		}
+	}
+	m.ui.views = views
+	m.ui.viewIdx = len(views) - 1
+	for _, view := range views {
+		m.getModel(view).Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	}
+	return nil
+}
+
+// paneModel returns the model of a pane, nil when the pane is empty
+func (m *Model) paneModel(pane int) tea.Model {
+	switch pane {
+	case listPane:
+		return m.pipelinesTable
+	case stagesPane:
+		if m.pipelineDetail.name != "" {
+			return m.pipelineDetail
+		}
+	case detailPane:
+		if m.split.detail != "" {
+			return m.getModel(m.split.detail)
+		}
+	}
+	return nil
+}
+
+// focusPane moves the focus to the next or previous pane which is not empty
+func (m *Model) focusPane(step int) {
+	for pane := m.split.focus + step; pane >= listPane && pane <= detailPane; pane += step {
+		if m.paneModel(pane) != nil {
+			m.split.focus = pane
+			return
+		}
+	}
+}
+
+// panesSize returns the width of the list pane and the height of the stages pane
+func (m *Model) panesSize() (int, int) {
+	return m.width * 2 / 5, (m.height - 1) * 11 / 20
+}
+
+// compactColumns only keeps the name and the status of the pipelines in the list pane, the other columns are hidden
+// The hidden name column is displayed when the name column is not configured
+func compactColumns(cols []table.Column, columns []Column, width int) []table.Column {
+	name, status := len(columns), 0
+	for i, c := range columns {
+		switch {
+		case c.Name == "name" && name == len(columns):
+			name = i
+		case c.Name == "status" && status == 0:
+			status = min(splitStatusWidth, width/3)
+			cols[i].Width = status
+			continue
+		}
+		cols[i].Width = 0
+	}
+	cols[name].Width = max(0, width-status)
+	return cols
+}
+
+// resize sizes the current view, the panes when they are displayed
+func (m *Model) resize() {
+	if m.isSplit() {
+		m.resizePanes()
+		return
+	}
+	m.getActiveModel().Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+}
+
+// resizePanes sizes the models of the panes to fit in the borders of the panes
+func (m *Model) resizePanes() {
+	listWidth, stagesHeight := m.panesSize()
+	height := m.height - 1
+	// The models remove the padding of the container and the status line from the window size,
+	// which are replaced by the borders of the panes
+	resize := func(model tea.Model, width, height int) {
+		model.Update(tea.WindowSizeMsg{Width: width, Height: height - 1})
+	}
+	resize(m.pipelinesTable, listWidth, height)
+	resize(m.pipelineDetail, m.width-listWidth, stagesHeight)
+	for _, view := range []string{codebuildView, actionView} {
+		resize(m.getModel(view), m.width-listWidth, height-stagesHeight)
+	}
+}
+
+// routeSplitView displays the selection of the list and stages panes in the next pane instead of a new view
+// It returns false when the view has to be displayed full screen
+func (m *Model) routeSplitView(msg tuiMsg) bool {
+	if !m.isSplit() {
+		return false
+	}
+	switch {
+	case msg.id == pipelineView && msg.src == pipelinesView:
+		m.pipelineDetail.Update(msg)
+		m.split.detail = ""
+		m.split.resource = ""
+		m.split.focus = stagesPane
+	case msg.src == pipelineView && slices.Contains(detailViews, msg.id):
+		m.getModel(msg.id).Update(msg)
+		m.split.detail = msg.id
+		if p, ok := msg.data.(PipelineResource); ok {
+			m.split.resource = p.StageName + "/" + p.ActionName + "/" + p.PipelineExecutionID
+		}
+		m.split.focus = detailPane
+	default:
+		return false
+	}
+	m.updateSplitPath()
+	return true
+}
+
+// paneOpen returns true when a view is displayed in a pane
+func (m *Model) paneOpen(view string) bool {
+	if !m.isSplit() {
+		return false
+	}
+	return view == pipelineView && m.pipelineDetail.name != "" || view == m.split.detail
+}
+
+// splitKey handles the keys of the split layout, it returns false when the key is handled by the focused pane
+func (m *Model) splitKey(msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, allKeys.NextPane):
+		m.focusPane(1)
+	case key.Matches(msg, allKeys.PrevPane):
+		m.focusPane(-1)
+	case key.Matches(msg, allKeys.Previous) && m.split.focus > listPane:
+		// The previous view is the previous pane
+		m.focusPane(-1)
+	default:
+		return false
+	}
+	return true
+}
+
+// syncPanes updates the panes following the focused pane after delay, cursor moves within the delay are ignored
+func (m *Model) syncPanes(delay time.Duration) tea.Cmd {
+	m.split.seq++
+	seq := m.split.seq
+	if delay == 0 {
+		return func() tea.Msg { return splitSync{seq: seq} }
+	}
+	return tea.Tick(delay, func(time.Time) tea.Msg { return splitSync{seq: seq} })
+}
+
+// updatePanes displays the pipeline selected in the list pane and the action selected in the stages pane
+func (m *Model) updatePanes(msg splitSync) {
+	if !m.isSplit() || msg.seq != m.split.seq || m.ui.refreshing {
+		return
+	}
+
+	switch m.split.focus {
+	case listPane:
+		name := rowPipelineName(m.pipelinesTable.SelectedRow())
+		if name == "" || name == m.pipelineDetail.name {
+			return
+		}
+		log.Debug().Str("model", "tui").Str("func", "Model.updatePanes").Msgf("pipeline: %v", name)
+		m.pipelineDetail.Update(tuiMsg{class: viewChange, src: pipelinesView, id: pipelineView, data: name})
+		m.split.detail = ""
+		m.split.resource = ""
+
+	case stagesPane:
+		row := m.pipelineDetail.SelectedRow()
+		if !isActionRow(row) {
+			return
+		}
+		view, p, err := m.pipelineDetail.selectComponent(row)
+		if err != nil {
+			return
+		}
+		resource := p.StageName + "/" + p.ActionName + "/" + p.PipelineExecutionID
+		if view == m.split.detail && resource == m.split.resource {
+			return
+		}
+		log.Debug().Str("model", "tui").Str("func", "Model.updatePanes").Msgf("action: %v", resource)
+		m.getModel(view).Update(tuiMsg{class: viewChange, src: pipelineView, id: view, data: p})
+		m.split.detail = view
+		m.split.resource = resource
+	}
+	m.updateSplitPath()
+}
+
+// updateSplitPath shows the pipeline and the action of the panes in the status line
+func (m *Model) updateSplitPath() {
+	m.ui.path[0], m.ui.path[1], m.ui.path[2] = m.pipelinesTable.name, m.pipelineDetail.name, ""
+	if m.split.detail != "" {
+		if p, ok := m.getModel(m.split.detail).(*ActionTable); ok {
+			m.ui.path[2] = p.name
+		} else {
+			m.ui.path[2] = m.codeBuildDetail.stageName
+		}
+	}
+}
+
+// splitView renders the panes, the focused pane is highlighted
+func (m *Model) splitView() string {
+	listWidth, stagesHeight := m.panesSize()
+	height := m.height - 1
+
+	pane := func(idx int, placeholder string, width, height int) string {
+		color := lipgloss.TerminalColor(lipgloss.Color("240"))
+		if idx == m.split.focus {
+			color = tint.Purple()
+		}
+		content := placeholder
+		if model := m.paneModel(idx); model != nil {
+			content = model.View()
+		} else {
+			content = lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render(content)
+		}
+		return lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(color).
+			Width(width - 2).
+			Height(height - 2).
+			MaxHeight(height).
+			Render(lipgloss.NewStyle().MaxWidth(width - 2).MaxHeight(height - 2).Render(content))
+	}
+
+	return lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		pane(listPane, "", listWidth, height),
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			pane(stagesPane, "Select a CodePipeline", m.width-listWidth, stagesHeight),
+			pane(detailPane, "Select an action", m.width-listWidth, height-stagesHeight),
+		),
+	)
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+
+	"github.com/fabio42/codeplumber/models/table"
+
+	"github.com/rs/zerolog/log"
+)
+
+// pinMarker prefixes the pinned pipelines in the pipelines table
+const pinMarker = "★ "
+
+// sessionState is what the user keeps across sessions, stored in a state file next to the configuration file
+type sessionState struct {
+	// Pinned are the names of the pipelines listed first
+	Pinned []string `json:"pinned,omitempty"`
+	// Views are the saved filter queries and sorts of the pipelines table
+	Views []savedView `json:"views,omitempty"`
+}
+
+// savedView is a named filter query and sort of the pipelines table
+type savedView struct {
+	Name  string `json:"name"`
+	Query string `json:"query,omitempty"`
+	Sort  string `json:"sort,omitempty"`
+}
+
+// readState returns the state of the previous sessions, an empty state when the state file does not exist
+func readState() sessionState {
+	var state sessionState
+	if config.StatePath == "" {
+		return state
+	}
+
+	data, err := os.ReadFile(config.StatePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Debug().Str("model", "tui").Str("func", "readState").Msgf("failed to read state %v: %v", config.StatePath, err)
+		}
+		return state
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		log.Debug().Str("model", "tui").Str("func", "readState").Msgf("ignoring invalid state %v: %v", config.StatePath, err)
+		return sessionState{}
+	}
+	return state
+}
+
+// save writes the state file
+func (s sessionState) save() error {
+	if config.StatePath == "" {
+		return nil
+	}
+	return writeJSONFile(config.StatePath, s)
+}
+
+func (s sessionState) isPinned(name string) bool {
+	return slices.Contains(s.Pinned, name)
+}
+
+// togglePin pins or unpins a pipeline
+func (s *sessionState) togglePin(name string) {
+	if idx := slices.Index(s.Pinned, name); idx >= 0 {
+		s.Pinned = slices.Delete(s.Pinned, idx, idx+1)
+		return
+	}
+	s.Pinned = append(s.Pinned, name)
+	slices.Sort(s.Pinned)
+}
+
+// saveView adds a view or replaces the view with the same name, and returns its index
+func (s *sessionState) saveView(view savedView) int {
+	for i, v := range s.Views {
+		if v.Name == view.Name {
+			s.Views[i] = view
+			return i
+		}
+	}
+	s.Views = append(s.Views, view)
+	return len(s.Views) - 1
+}
+
+// pinnedFirst returns the pinned pipelines of all the rows, whatever the filter, then the other filtered rows
+// The pinned rows are marked, the order of the rows is kept otherwise; the rows of the table are never modified
+func (s sessionState) pinnedFirst(all, filtered []table.Row) []table.Row {
+	if len(s.Pinned) == 0 {
+		return filtered
+	}
+	pinned := make([]table.Row, 0, len(s.Pinned))
+	for _, row := range all {
+		if s.isPinned(rowPipelineName(row)) {
+			marked := slices.Clone(row)
+			marked[0] = pinMarker + marked[0]
+			pinned = append(pinned, marked)
+		}
+	}
+	others := make([]table.Row, 0, len(filtered))
+	for _, row := range filtered {
+		if !s.isPinned(rowPipelineName(row)) {
+			others = append(others, row)
+		}
+	}
+	return append(pinned, others...)
+}
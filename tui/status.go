@@ -18,6 +18,8 @@ type StatusLines struct {
 	sInput []textinput.Model
 
 	notification  notification
+	historyIdx    int
+	palette       palette
 	width, height int
 	ui            *uiData
 }
@@ -31,7 +33,7 @@ type notification struct {
 
 func newInput(prompt, placeholder string, s lipgloss.Style) textinput.Model {
 	ti := textinput.New()
-	ti.CharLimit = 64
+	ti.CharLimit = 256
 	ti.Width = 64
 	ti.Prompt = prompt
 	ti.Placeholder = fmt.Sprintf("%-64v", placeholder)
@@ -50,9 +52,10 @@ func NewStatusLines(ui *uiData) *StatusLines {
 	}
 
 	return &StatusLines{
-		sInput: xSearch,
-		tInput: xText,
-		ui:     ui,
+		sInput:  xSearch,
+		tInput:  xText,
+		palette: newPalette(ui.StatusLineSearchStyle()),
+		ui:      ui,
 	}
 }
 
@@ -90,14 +93,41 @@ func (m *StatusLines) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case searchMsg:
-			if key.Matches(msg, pagerKeys.Select) {
+			switch {
+			case key.Matches(msg, pagerKeys.Select):
 				m.sInput[m.ui.viewIdx].Blur()
 				data := m.sInput[m.ui.viewIdx].Value()
 				m.response(data, len(data) > 0)
+			case key.Matches(msg, pagerKeys.Up):
+				if m.historyIdx > 0 {
+					m.historyIdx--
+					m.sInput[m.ui.viewIdx].SetValue(m.ui.queryHistory[m.historyIdx])
+				}
+			case key.Matches(msg, pagerKeys.Down):
+				if m.historyIdx < len(m.ui.queryHistory)-1 {
+					m.historyIdx++
+					m.sInput[m.ui.viewIdx].SetValue(m.ui.queryHistory[m.historyIdx])
+				} else {
+					m.historyIdx = len(m.ui.queryHistory)
+					m.sInput[m.ui.viewIdx].SetValue("")
+				}
+			}
+
+		case paletteMsg:
+			switch {
+			case key.Matches(msg, pagerKeys.Cancel):
+				m.palette.close()
+				m.notification = notification{}
+				m.ui.inputFocused = false
+			case m.palette.update(msg):
+				m.notification.ref = m.palette.selection()
+				m.palette.close()
+				m.response("", true)
 			}
+			return m, nil
 
 		default:
-			if m.notification.kind == "error" {
+			if m.notification.kind == errorMsg || m.notification.kind == infoMsg {
 				m.ui.inputFocused = false
 				m.notification.kind = ""
 			}
@@ -107,10 +137,14 @@ func (m *StatusLines) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.ui.inputFocused = true
 		m.notification = msg
 		switch msg.kind {
-		case "reason":
+		case "text", "reason":
+			m.tInput[m.ui.viewIdx].Reset()
 			m.tInput[m.ui.viewIdx].Focus()
 		case searchMsg:
+			m.historyIdx = len(m.ui.queryHistory)
 			m.sInput[m.ui.viewIdx].Focus()
+		case paletteMsg:
+			m.palette.open(msg.ref.([]paletteItem))
 		}
 	}
 
@@ -128,13 +162,74 @@ func (m *StatusLines) View() string {
 		return lipgloss.NewStyle().Foreground(tint.Yellow()).Render(m.notification.prompt) + m.tInput[m.ui.viewIdx].View()
 	case "confirm":
 		return lipgloss.NewStyle().Foreground(tint.Yellow()).Render("CONFIRM: ") + m.notification.prompt + (" (y/n)")
+	case paletteMsg:
+		// The spinner and the padding of the status line take 3 cells
+		return m.palette.View(m.width - 3)
 	case errorMsg:
 		return lipgloss.NewStyle().Foreground(tint.Red()).Render("ERROR: ") + m.notification.prompt + " (press any key to continue)"
+	case infoMsg:
+		return lipgloss.NewStyle().Foreground(tint.Green()).Render("INFO: ") + m.notification.prompt + " (press any key to continue)"
 	default:
-		prompt := lipgloss.NewStyle().Bold(true).Foreground(tint.White()).Render("Path:")
-		path := strings.Join(m.ui.path[0:m.ui.viewIdx+1], "/")
-		return prompt + " /" + m.truncatePath(path, prompt+" /")
+		_, path, filter := m.breadcrumb()
+		return pathPrompt() + path + filter
+	}
+}
+
+func pathPrompt() string {
+	return lipgloss.NewStyle().Bold(true).Foreground(tint.White()).Render("Path:") + " /"
+}
+
+// breadcrumb returns the segments of the path, the path truncated to the width of the status line and the filter of the view
+func (m *StatusLines) breadcrumb() ([]string, string, string) {
+	segments := m.ui.path[0 : m.ui.viewIdx+1]
+	if m.ui.split && m.ui.viewIdx == 0 {
+		// The pipeline and the action of the panes, when selected
+		segments = strings.Split(strings.TrimRight(strings.Join(m.ui.path[0:3], "/"), "/"), "/")
+	}
+	path := strings.Join(segments, "/")
+
+	var filter string
+	if m.ui.currentView() == pipelinesView {
+		if m.ui.savedView != "" {
+			filter += " " + lipgloss.NewStyle().Bold(true).Foreground(tint.White()).Render("View:") + " " + m.ui.savedView
+		}
+		if m.ui.query != "" {
+			filter += " " + lipgloss.NewStyle().Bold(true).Foreground(tint.White()).Render("Filter:") + " " + m.ui.query
+		}
+	}
+	return segments, m.truncatePath(path, pathPrompt()+filter), filter
+}
+
+// pathSegment returns the index of the segment of the path displayed at x, the position in the status line
+// The root of the path is the first segment, it returns false when no segment is displayed at x
+func (m *StatusLines) pathSegment(x int) (int, bool) {
+	if m.notification.kind != "" {
+		return 0, false
+	}
+	segments, path, _ := m.breadcrumb()
+
+	x -= lipgloss.Width(pathPrompt())
+	switch {
+	case x == -1:
+		// The slash of the prompt
+		return 0, true
+	case x < 0 || x >= len([]rune(path)):
+		return 0, false
+	}
+	// The start of a truncated path is not displayed
+	x += len([]rune(strings.Join(segments, "/"))) - len([]rune(path))
+	for i, segment := range segments {
+		n := len([]rune(segment))
+		if x < n {
+			return i, true
+		}
+		// The separators do not belong to a segment
+		if x == n {
+			return 0, false
+		}
+		x -= n + 1
 	}
+	return 0, false
 }
 
 func (m *StatusLines) response(data string, trigger bool) {
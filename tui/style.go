@@ -43,11 +43,11 @@ func (c *uiData) statusColor(status string) lipgloss.TerminalColor {
 	switch strings.ToLower(strings.TrimSpace(status)) {
 	case "inprogress", "waiting":
 		return tint.Blue()
-	case "enabled", "succeeded":
+	case "enabled", "succeeded", "add":
 		return tint.Green()
-	case "disabled", "failed":
+	case "disabled", "failed", "remove", "error":
 		return tint.Red()
-	case "stopped", "unknown":
+	case "stopped", "unknown", "modify":
 		return tint.Yellow()
 	case "resuming":
 		return tint.BrightPurple()